@@ -0,0 +1,253 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"svm/internal/config"
+	"svm/internal/sdk"
+	"svm/internal/utils"
+
+	"github.com/spf13/cobra"
+)
+
+func initCacheCmd() {
+	cacheCmd := &cobra.Command{
+		Use:   "cache",
+		Short: "管理下载的安装包缓存",
+		Long: `管理安装目录下 cache 目录中的安装包、未完成的下载和版本列表缓存。
+删除安装包时会同步更新配置，之后安装对应版本会重新下载。`,
+	}
+
+	cacheListCmd := &cobra.Command{
+		Use:   "list [sdk]",
+		Short: "列出缓存的文件",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, files, err := loadCacheFiles(args)
+			if err != nil {
+				return err
+			}
+
+			if len(files) == 0 {
+				utils.Log.Info(fmt.Sprintf("缓存目录 %s 中没有文件", cfg.GetCacheDir()))
+				return nil
+			}
+
+			var metadataCount int
+			var metadataSize int64
+			for _, file := range files {
+				switch file.Kind {
+				case sdk.CacheMetadata:
+					metadataCount++
+					metadataSize += file.Size
+				case sdk.CachePartial:
+					utils.Log.Custom(utils.IconStar, utils.Yellow, "", fmt.Sprintf("%s  %s  %s  (未完成的下载)",
+						file.SDK, file.Path, utils.FormatBytes(file.Size)))
+				default:
+					utils.Log.Custom(utils.IconStar, utils.Green, "", fmt.Sprintf("%s %s  %s  %s  %s",
+						file.SDK, cacheVersionLabel(file), file.Path, utils.FormatBytes(file.Size), file.ModTime.Format("2006-01-02")))
+				}
+			}
+			if metadataCount > 0 {
+				utils.Log.Info(fmt.Sprintf("版本列表缓存: %d 个文件，%s", metadataCount, utils.FormatBytes(metadataSize)))
+			}
+			return nil
+		},
+	}
+
+	cacheSizeCmd := &cobra.Command{
+		Use:   "size [sdk]",
+		Short: "显示缓存占用的空间",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			_, files, err := loadCacheFiles(args)
+			if err != nil {
+				return err
+			}
+
+			// 按SDK汇总，ListCacheFiles 已按SDK排序
+			var names []string
+			sizes := make(map[string]int64)
+			var total int64
+			for _, file := range files {
+				if _, ok := sizes[file.SDK]; !ok {
+					names = append(names, file.SDK)
+				}
+				sizes[file.SDK] += file.Size
+				total += file.Size
+			}
+
+			for _, name := range names {
+				utils.Log.Custom(utils.IconStar, utils.Green, "", fmt.Sprintf("%s: %s", name, utils.FormatBytes(sizes[name])))
+			}
+			utils.Log.Info(fmt.Sprintf("总计: %s", utils.FormatBytes(total)))
+			return nil
+		},
+	}
+
+	cacheCleanCmd := &cobra.Command{
+		Use:   "clean [sdk] [version]",
+		Short: "删除缓存的文件",
+		Long: `删除缓存的文件：
+不指定参数时删除所有缓存；指定SDK时删除该SDK的安装包、未完成的下载和版本列表缓存；
+同时指定版本时只删除该版本的安装包。`,
+		Example: `  svm cache clean
+  svm cache clean node
+  svm cache clean node 20.11.1`,
+		Args: cobra.MaximumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, files, err := loadCacheFiles(args)
+			if err != nil {
+				return err
+			}
+
+			if len(args) == 2 {
+				version := strings.TrimPrefix(args[1], "v")
+				var matched []sdk.CacheFile
+				for _, file := range files {
+					if file.Kind == sdk.CacheArchive && strings.TrimPrefix(file.Version, "v") == version {
+						matched = append(matched, file)
+					}
+				}
+				if len(matched) == 0 {
+					return fmt.Errorf("没有 %s %s 的缓存", args[0], args[1])
+				}
+				files = matched
+			}
+
+			return removeCacheFiles(cfg, files)
+		},
+	}
+
+	cachePruneCmd := &cobra.Command{
+		Use:   "prune",
+		Short: "清理不再需要的安装包",
+		Long: `删除未完成的下载和配置中没有记录的文件，然后按下载时间从旧到新删除安装包：
+指定 --max-size 时删除到缓存总大小不超过该值为止，否则删除所有安装包；
+指定 --keep-installed 时保留已安装版本的安装包。版本列表缓存不会被删除。`,
+		Example: `  svm cache prune --keep-installed
+  svm cache prune --keep-installed --max-size 5G`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			keepInstalled, _ := cmd.Flags().GetBool("keep-installed")
+			maxSizeText, _ := cmd.Flags().GetString("max-size")
+
+			maxSize := int64(-1)
+			if maxSizeText != "" {
+				size, err := utils.ParseBytes(maxSizeText)
+				if err != nil {
+					return err
+				}
+				maxSize = size
+			}
+
+			cfg, files, err := loadCacheFiles(nil)
+			if err != nil {
+				return err
+			}
+
+			var total int64
+			var removals, candidates []sdk.CacheFile
+			for _, file := range files {
+				total += file.Size
+				switch {
+				case file.Kind == sdk.CacheMetadata:
+				case file.Kind == sdk.CachePartial || file.Version == "":
+					// 未完成的下载和无法对应到版本的文件总是删除
+					removals = append(removals, file)
+					total -= file.Size
+				case keepInstalled && file.Installed:
+				default:
+					candidates = append(candidates, file)
+				}
+			}
+
+			// 按修改时间从旧到新删除，直到不超过大小限制
+			sort.SliceStable(candidates, func(i, j int) bool {
+				return candidates[i].ModTime.Before(candidates[j].ModTime)
+			})
+			for _, file := range candidates {
+				if maxSize >= 0 && total <= maxSize {
+					break
+				}
+				removals = append(removals, file)
+				total -= file.Size
+			}
+
+			if len(removals) == 0 {
+				utils.Log.Info(fmt.Sprintf("没有需要清理的文件，缓存大小: %s", utils.FormatBytes(total)))
+				return nil
+			}
+			if err := removeCacheFiles(cfg, removals); err != nil {
+				return err
+			}
+
+			if maxSize >= 0 && total > maxSize {
+				utils.Log.Warning(fmt.Sprintf("缓存大小 %s 仍超过 %s，剩余的是已安装版本的安装包或版本列表缓存",
+					utils.FormatBytes(total), utils.FormatBytes(maxSize)))
+			}
+			return nil
+		},
+	}
+	cachePruneCmd.Flags().Bool("keep-installed", false, "保留已安装版本的安装包")
+	cachePruneCmd.Flags().String("max-size", "", "缓存的最大总大小，例如 5G、500M")
+
+	cacheCmd.AddCommand(cacheListCmd, cacheSizeCmd, cacheCleanCmd, cachePruneCmd)
+	rootCmd.AddCommand(cacheCmd)
+}
+
+// loadCacheFiles 加载配置并列出缓存文件，args 的第一个参数为SDK名称
+func loadCacheFiles(args []string) (*config.Config, []sdk.CacheFile, error) {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return nil, nil, fmt.Errorf("加载配置失败: %w", err)
+	}
+
+	var sdkName string
+	if len(args) > 0 {
+		sdkName = args[0]
+		if GetSDK(sdkName) == nil {
+			return nil, nil, fmt.Errorf("不支持的SDK: %s", sdkName)
+		}
+	}
+
+	files, err := sdk.ListCacheFiles(cfg, sdkName)
+	if err != nil {
+		return nil, nil, err
+	}
+	return cfg, files, nil
+}
+
+// removeCacheFiles 删除缓存文件并清除配置中指向已不存在文件的缓存信息
+func removeCacheFiles(cfg *config.Config, files []sdk.CacheFile) error {
+	var freed int64
+	for _, file := range files {
+		if err := sdk.RemoveCacheFile(cfg, file); err != nil {
+			return err
+		}
+		freed += file.Size
+		if file.Kind != sdk.CacheMetadata {
+			utils.Log.Delete(fmt.Sprintf("已删除 %s", file.Path))
+		}
+	}
+
+	if _, err := sdk.ReconcileCacheEntries(cfg); err != nil {
+		return err
+	}
+
+	utils.Log.Success(fmt.Sprintf("已删除 %d 个文件，释放 %s", len(files), utils.FormatBytes(freed)))
+	return nil
+}
+
+// cacheVersionLabel 返回安装包对应的版本描述
+func cacheVersionLabel(file sdk.CacheFile) string {
+	switch {
+	case file.Version == "":
+		return "(未知版本)"
+	case file.Installed:
+		return file.Version + " (已安装)"
+	default:
+		return file.Version
+	}
+}
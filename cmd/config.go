@@ -4,8 +4,14 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
 	"svm/internal/config"
+	"svm/internal/sdk"
 	"svm/internal/utils"
+	"time"
 
 	"github.com/spf13/cobra"
 )
@@ -19,8 +25,14 @@ var configCmd = &cobra.Command{
 var setInstallDirCmd = &cobra.Command{
 	Use:   "set-install-dir <directory>",
 	Short: "设置SDK安装目录",
-	Long:  `设置SDK的安装目录，所有SDK将会安装到这个目录下`,
-	Args:  cobra.ExactArgs(1),
+	Long: `设置SDK的安装目录，之后的SDK将会安装到这个目录下。
+默认已安装的SDK保持在原位置；使用 --migrate 将已安装的SDK和缓存的安装包移动到新目录，
+同一文件系统中直接移动，否则复制并校验后删除原目录，然后更新配置中的路径、重新创建 current 链接并设置环境变量。
+迁移中断后重新运行同一命令即可继续。登记的外部目录不会移动。`,
+	Example: `  svm config set-install-dir D:\SDKs
+  svm config set-install-dir /data/svm --migrate`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		// 获取目标目录的绝对路径
 		dir := args[0]
@@ -29,17 +41,21 @@ var setInstallDirCmd = &cobra.Command{
 			return fmt.Errorf("获取绝对路径失败: %w", err)
 		}
 
-		// 创建目录（如果不存在）
-		if err := os.MkdirAll(absDir, 0755); err != nil {
-			return fmt.Errorf("创建目录失败: %w", err)
-		}
-
 		// 加载配置
 		cfg, err := config.LoadConfig()
 		if err != nil {
 			return fmt.Errorf("加载配置失败: %w", err)
 		}
 
+		if migrate, _ := cmd.Flags().GetBool("migrate"); migrate {
+			return migrateInstallDir(cfg, absDir)
+		}
+
+		// 创建目录（如果不存在）
+		if err := os.MkdirAll(absDir, 0755); err != nil {
+			return fmt.Errorf("创建目录失败: %w", err)
+		}
+
 		// 如果目录已经存在且不为空，提示用户
 		entries, err := os.ReadDir(absDir)
 		if err != nil {
@@ -59,6 +75,74 @@ var setInstallDirCmd = &cobra.Command{
 	},
 }
 
+// migrateInstallDir 将已安装的SDK和缓存移动到新的安装目录，然后恢复各SDK的当前版本
+func migrateInstallDir(cfg *config.Config, absDir string) error {
+	setting, _ := config.LookupSetting("install_dir")
+	if _, source := cfg.GetSetting(setting); source == config.SourceEnv {
+		return fmt.Errorf("安装目录由环境变量 %s 指定，请先取消该环境变量", setting.EnvName())
+	}
+
+	oldDir := cfg.InstallDir
+	if err := sdk.RelocateInstallDir(cfg, absDir, sdkNames); err != nil {
+		return fmt.Errorf("迁移安装目录失败: %w，解决问题后重新运行该命令即可继续", err)
+	}
+
+	// SDK实例使用新的安装目录
+	resetSDKs()
+	restoreCurrentVersions(cfg)
+
+	utils.Log.Success(fmt.Sprintf("已将安装目录从 %s 迁移到: %s", oldDir, absDir))
+	return nil
+}
+
+// componentSwitcher 由按组件管理版本的SDK实现，例如 .NET
+type componentSwitcher interface {
+	SetComponentType(componentType string)
+}
+
+// restoreCurrentVersions 为各SDK的当前版本重新创建 current 链接并设置环境变量，跳过目录不存在的版本
+func restoreCurrentVersions(cfg *config.Config) {
+	for _, name := range sdkNames {
+		sdkInstance := GetSDK(name)
+		sdkConfig := cfg.SDKs[name]
+
+		switcher, ok := sdkInstance.(componentSwitcher)
+		if !ok {
+			if sdkConfig.CurrentVersion != "" {
+				useInstalledVersion(cfg, sdkInstance, sdkConfig.CurrentVersion)
+			}
+			continue
+		}
+
+		components := make([]string, 0, len(sdkConfig.Components))
+		for component := range sdkConfig.Components {
+			components = append(components, component)
+		}
+		sort.Strings(components)
+		for _, component := range components {
+			switcher.SetComponentType(component)
+			useInstalledVersion(cfg, sdkInstance, sdkConfig.Components[component])
+		}
+	}
+}
+
+// useInstalledVersion 切换到已安装的版本，版本目录不存在时只给出提示，不会自动安装
+func useInstalledVersion(cfg *config.Config, sdkInstance sdk.SDK, version string) {
+	name := sdkInstance.GetName()
+	info, ok := cfg.GetVersionInfo(name, version)
+	if !ok || info.InstallDir == "" {
+		utils.Log.Warning(fmt.Sprintf("%s %s 没有安装记录，跳过", name, version))
+		return
+	}
+	if exists, _ := utils.CheckDirExists(info.InstallDir); !exists {
+		utils.Log.Warning(fmt.Sprintf("%s %s 的目录 %s 不存在，跳过", name, version, info.InstallDir))
+		return
+	}
+	if err := sdkInstance.Use(version); err != nil {
+		utils.Log.Warning(fmt.Sprintf("恢复 %s %s 失败: %v", name, version, err))
+	}
+}
+
 var getInstallDirCmd = &cobra.Command{
 	Use:   "get-install-dir",
 	Short: "获取当前的SDK安装目录",
@@ -70,12 +154,500 @@ var getInstallDirCmd = &cobra.Command{
 		}
 
 		utils.Log.Info(fmt.Sprintf("当前安装目录: %s", cfg.InstallDir))
+		utils.Log.Info(fmt.Sprintf("配置文件: %s", config.GetConfigFilePath()))
+		return nil
+	},
+}
+
+var setKeyringCmd = &cobra.Command{
+	Use:   "set-keyring <sdk> [file]",
+	Short: "设置验证签名使用的公钥环",
+	Long: `设置验证校验文件签名时使用的OpenPGP公钥环（二进制或ASCII armor格式），
+适用于使用本地镜像或需要自行管理发布方公钥的场景。省略文件时恢复默认公钥环。
+等同于 svm config set <sdk>.keyring <file>。`,
+	Args:         cobra.RangeArgs(1, 2),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		sdkName := args[0]
+		cfg, setting, values, err := applySetting(sdkName, "keyring", args[1:])
+		if err != nil {
+			return err
+		}
+
+		if len(values) == 0 {
+			utils.Log.Success(fmt.Sprintf("已恢复 %s 的默认公钥环: %s", sdkName, config.GetDefaultKeyringPath(sdkName)))
+		} else if keyring, err := utils.LoadKeyring(values[0]); err == nil {
+			utils.Log.Success(fmt.Sprintf("已将 %s 的公钥环设置为: %s（%d 个公钥）", sdkName, values[0], len(keyring)))
+		}
+		warnEnvOverride(cfg, setting)
+		return nil
+	},
+}
+
+var refreshKeyringCmd = &cobra.Command{
+	Use:   "refresh-keyring <sdk>",
+	Short: "从发布方获取当前的公钥",
+	Long: `从发布方获取当前有效的OpenPGP公钥（Node.js 为 nodejs/release-keys），保存到默认公钥环路径，
+之后的签名验证使用保存的公钥代替内置的公钥。用于发布方新增了签名者而 svm 内置的公钥尚未更新的情况。`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		sdkName := args[0]
+		sdkInstance := GetSDK(sdkName)
+		if sdkInstance == nil {
+			return fmt.Errorf("不支持的SDK: %s", sdkName)
+		}
+		refresher, ok := sdkInstance.(interface{ RefreshKeyring() (int, string, error) })
+		if !ok {
+			return fmt.Errorf("%s 不使用签名验证", sdkName)
+		}
+
+		count, keyringPath, err := refresher.RefreshKeyring()
+		if err != nil {
+			return err
+		}
+		utils.Log.Success(fmt.Sprintf("已保存 %d 个公钥到 %s", count, keyringPath))
+
+		cfg, err := config.LoadConfig()
+		if err == nil && cfg.GetKeyringPath(sdkName) != keyringPath {
+			utils.Log.Warning(fmt.Sprintf("%s 当前使用指定的公钥环 %s，使用 svm config set-keyring %s 恢复默认公钥环后生效", sdkName, cfg.GetKeyringPath(sdkName), sdkName))
+		}
+		return nil
+	},
+}
+
+var setMirrorCmd = &cobra.Command{
+	Use:   "set-mirror <sdk> [url...]",
+	Short: "设置SDK的镜像地址",
+	Long: `设置SDK的镜像地址，多个地址按顺序尝试，一个镜像失败时使用下一个。
+镜像需要与官方地址有相同的目录结构，例如 Node.js 的 https://npmmirror.com/mirrors/node 对应 https://nodejs.org/dist。
+配置镜像后不再访问官方地址，如需在镜像都失败时回退到官方地址，请将官方地址写在最后。
+默认同时设置版本元数据和下载文件的镜像，可以用 --metadata 或 --archives 只设置其中一种。省略地址时恢复官方地址。
+等同于 svm config set <sdk>.mirror（或 <sdk>.mirror.metadata、<sdk>.mirror.archives）。`,
+	Example: `  svm config set-mirror node https://npmmirror.com/mirrors/node https://nodejs.org/dist
+  svm config set-mirror go --metadata https://golang.google.cn/dl
+  svm config set-mirror go --archives https://golang.google.cn/dl https://mirrors.aliyun.com/golang
+  svm config set-mirror python`,
+	Args:         cobra.MinimumNArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		sdkName, urls := args[0], args[1:]
+
+		// 分别对应 <sdk>.mirror、<sdk>.mirror.metadata 和 <sdk>.mirror.archives
+		metadataOnly, _ := cmd.Flags().GetBool("metadata")
+		archivesOnly, _ := cmd.Flags().GetBool("archives")
+		key := "mirror"
+		switch {
+		case metadataOnly && !archivesOnly:
+			key = "mirror.metadata"
+		case archivesOnly && !metadataOnly:
+			key = "mirror.archives"
+		}
+
+		cfg, setting, _, err := applySetting(sdkName, key, urls)
+		if err != nil {
+			return err
+		}
+
+		if len(urls) == 0 {
+			utils.Log.Success(fmt.Sprintf("已恢复 %s 的官方地址", sdkName))
+		} else {
+			utils.Log.Success(fmt.Sprintf("已设置 %s 的镜像地址", sdkName))
+		}
+		printMirrors(sdkName, cfg.GetMirrors(sdkName))
+		warnEnvOverride(cfg, setting)
+		return nil
+	},
+}
+
+var getMirrorCmd = &cobra.Command{
+	Use:   "get-mirror <sdk>",
+	Short: "显示SDK的镜像地址",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := checkSDKName(args[0]); err != nil {
+			return err
+		}
+
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("加载配置失败: %w", err)
+		}
+
+		printMirrors(args[0], cfg.GetMirrors(args[0]))
 		return nil
 	},
 }
 
+// printMirrors 显示SDK的镜像地址
+func printMirrors(sdkName string, mirrors config.MirrorConfig) {
+	for _, item := range []struct {
+		title string
+		urls  []string
+	}{
+		{"版本元数据", mirrors.Metadata},
+		{"下载文件", mirrors.Archives},
+	} {
+		if len(item.urls) == 0 {
+			utils.Log.Info(fmt.Sprintf("%s %s: 官方地址", sdkName, item.title))
+			continue
+		}
+
+		utils.Log.Info(fmt.Sprintf("%s %s:", sdkName, item.title))
+		for i, mirrorURL := range item.urls {
+			utils.Log.Custom(utils.IconStar, utils.Green, "", fmt.Sprintf("%d. %s", i+1, mirrorURL))
+		}
+	}
+}
+
+var setNetworkCmd = &cobra.Command{
+	Use:   "set-network",
+	Short: "设置网络请求的根证书、超时、重试和User-Agent",
+	Long: `设置所有网络请求共用的配置，只修改指定的选项。
+代理从 HTTPS_PROXY、HTTP_PROXY、NO_PROXY 环境变量读取；
+使用会解密TLS流量的企业代理时，可以用 --ca-cert 添加代理的根证书。
+各选项分别对应配置项 network.ca_certs、network.timeout、network.retries 和 network.user_agent。`,
+	Example: `  svm config set-network --ca-cert /etc/ssl/corp-root.pem --timeout 120
+  svm config set-network --retries 5 --user-agent "svm-ci"
+  svm config set-network --ca-cert ""`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		flags := cmd.Flags()
+
+		// 每个选项对应一个配置项，全部检查通过后再写入；空值和 0 恢复默认值
+		var changes []settingChange
+		add := func(key string, args ...string) error {
+			change, err := parseSettingChange(key, args)
+			if err == nil {
+				changes = append(changes, change)
+			}
+			return err
+		}
+
+		if flags.Changed("ca-cert") {
+			files, _ := flags.GetStringSlice("ca-cert")
+			files = slices.DeleteFunc(files, func(file string) bool { return file == "" })
+			if err := add("network.ca_certs", files...); err != nil {
+				return err
+			}
+		}
+		if flags.Changed("timeout") {
+			timeout, _ := flags.GetInt("timeout")
+			var value []string
+			if timeout != 0 {
+				value = []string{strconv.Itoa(timeout)}
+			}
+			if err := add("network.timeout", value...); err != nil {
+				return err
+			}
+		}
+		if flags.Changed("retries") {
+			retries, _ := flags.GetInt("retries")
+			if err := add("network.retries", strconv.Itoa(retries)); err != nil {
+				return err
+			}
+		}
+		if flags.Changed("user-agent") {
+			userAgent, _ := flags.GetString("user-agent")
+			var value []string
+			if userAgent != "" {
+				value = []string{userAgent}
+			}
+			if err := add("network.user_agent", value...); err != nil {
+				return err
+			}
+		}
+
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("加载配置失败: %w", err)
+		}
+		for _, change := range changes {
+			if err := cfg.SetSetting(change.setting, change.values); err != nil {
+				return fmt.Errorf("保存配置失败: %w", err)
+			}
+		}
+
+		opts := httpOptions(cfg.Network)
+		utils.Log.Success("已保存网络配置")
+		if len(opts.CACertFiles) > 0 {
+			utils.Log.Info(fmt.Sprintf("根证书: %s", strings.Join(opts.CACertFiles, ", ")))
+		}
+		utils.Log.Info(fmt.Sprintf("超时: %s，重试: %d 次", opts.RequestTimeout, opts.Retries))
+		if opts.UserAgent != "" {
+			utils.Log.Info(fmt.Sprintf("User-Agent: %s", opts.UserAgent))
+		}
+		for _, change := range changes {
+			warnEnvOverride(cfg, change.setting)
+		}
+		return nil
+	},
+}
+
+var setMetadataTTLCmd = &cobra.Command{
+	Use:   "set-metadata-ttl <duration>",
+	Short: "设置版本列表缓存的有效期",
+	Long: `设置版本列表等远程元数据的缓存有效期，例如 30m、6h、24h，传入空字符串恢复默认值。
+有效期内直接使用缓存，过期后使用 ETag / Last-Modified 向服务器确认内容是否变化；
+设置为 0 时每次都向服务器确认。使用 --offline 时始终只使用缓存。
+等同于 svm config set cache.metadata_ttl <duration>。`,
+	Example: `  svm config set-metadata-ttl 24h
+  svm config set-metadata-ttl 0
+  svm config set-metadata-ttl ""`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var value []string
+		if args[0] != "" {
+			value = args
+		}
+		cfg, setting, values, err := applySetting("", "cache.metadata_ttl", value)
+		if err != nil {
+			return err
+		}
+
+		ttl := utils.DefaultMetadataTTL
+		if len(values) > 0 {
+			ttl, _ = time.ParseDuration(values[0])
+		}
+		utils.Log.Success(fmt.Sprintf("版本列表缓存有效期: %s", ttl))
+		warnEnvOverride(cfg, setting)
+		return nil
+	},
+}
+
+var validateConfigCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "检查配置文件",
+	Long: `检查配置文件中的问题：未知的配置项、不存在的安装目录和缓存文件，
+以及没有安装记录或 current 链接不一致的当前版本。发现问题时以非零状态退出。`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("加载配置失败: %w", err)
+		}
+
+		issues, err := cfg.Validate()
+		if err != nil {
+			return err
+		}
+		if len(issues) == 0 {
+			utils.Log.Success("配置文件没有问题")
+			return nil
+		}
+
+		for _, issue := range issues {
+			utils.Log.Warning(fmt.Sprintf("%s: %s", issue.Key, issue.Message))
+		}
+		return fmt.Errorf("配置文件中发现 %d 个问题", len(issues))
+	},
+}
+
+var getConfigCmd = &cobra.Command{
+	Use:   "get <key>",
+	Short: "显示配置项的值",
+	Long: `显示配置项的生效值，未设置时显示默认值。列表以逗号分隔。
+使用 svm config list 查看所有配置项及其来源。`,
+	Example: `  svm config get network.timeout
+  svm config get node.mirror`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		setting, err := lookupSetting(args[0])
+		if err != nil {
+			return err
+		}
+
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("加载配置失败: %w", err)
+		}
+
+		value, _ := cfg.GetSetting(setting)
+		fmt.Println(value)
+		return nil
+	},
+}
+
+var setConfigCmd = &cobra.Command{
+	Use:   "set <key> <value...>",
+	Short: "设置配置项",
+	Long: `设置配置项并写入配置文件，设置前检查值是否有效。
+列表配置项（根证书、镜像地址）可以传入多个值或用逗号分隔；<sdk>.mirror 同时设置版本元数据和下载文件的镜像。`,
+	Example: `  svm config set node.mirror https://npmmirror.com/mirrors/node
+  svm config set go.mirror.metadata https://golang.google.cn/dl
+  svm config set network.timeout 120
+  svm config set cache.metadata_ttl 24h`,
+	Args:         cobra.MinimumNArgs(2),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		setting, err := lookupSetting(args[0])
+		if err != nil {
+			return err
+		}
+		values, err := config.ParseSettingValue(setting, args[1:])
+		if err != nil {
+			return err
+		}
+
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("加载配置失败: %w", err)
+		}
+		if err := cfg.SetSetting(setting, values); err != nil {
+			return fmt.Errorf("保存配置失败: %w", err)
+		}
+
+		utils.Log.Success(fmt.Sprintf("已设置 %s = %s", setting.Key, strings.Join(values, ",")))
+		warnEnvOverride(cfg, setting)
+		return nil
+	},
+}
+
+var unsetConfigCmd = &cobra.Command{
+	Use:          "unset <key>",
+	Short:        "从配置文件中移除配置项，恢复默认值",
+	Example:      `  svm config unset node.mirror`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		setting, err := lookupSetting(args[0])
+		if err != nil {
+			return err
+		}
+
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("加载配置失败: %w", err)
+		}
+		if err := cfg.UnsetSetting(setting); err != nil {
+			return fmt.Errorf("保存配置失败: %w", err)
+		}
+
+		utils.Log.Success(fmt.Sprintf("已恢复 %s 的默认值", setting.Key))
+		warnEnvOverride(cfg, setting)
+		return nil
+	},
+}
+
+var listConfigCmd = &cobra.Command{
+	Use:   "list",
+	Short: "列出所有配置项的生效值及来源",
+	Long: `列出所有配置项的生效值，以及值的来源：
+  default  未设置，使用默认值
+  file     配置文件
+  env      环境变量，名称为 SVM_ 加上大写的配置项路径，例如 SVM_NETWORK_TIMEOUT、SVM_NODE_MIRROR`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("加载配置失败: %w", err)
+		}
+
+		utils.Log.Info(fmt.Sprintf("配置文件: %s", config.GetConfigFilePath()))
+		for _, setting := range config.Settings(sdkNames) {
+			value, source := cfg.GetSetting(setting)
+			if value == "" {
+				value = "(未设置)"
+			}
+			fmt.Printf("%-28s %-8s %s\n", setting.Key, source, value)
+		}
+		return nil
+	},
+}
+
+// lookupSetting 查找配置项，SDK配置项需要是支持的SDK
+func lookupSetting(key string) (config.Setting, error) {
+	setting, err := config.LookupSetting(key)
+	if err != nil {
+		return setting, err
+	}
+	if setting.SDK != "" {
+		return setting, checkSDKName(setting.SDK)
+	}
+	return setting, nil
+}
+
+// settingChange 表示一个已检查的配置项修改，values 为空时恢复默认值
+type settingChange struct {
+	setting config.Setting
+	values  []string
+}
+
+// parseSettingChange 查找配置项并检查要设置的值，args 为空时表示恢复默认值
+func parseSettingChange(key string, args []string) (settingChange, error) {
+	setting, err := lookupSetting(key)
+	if err != nil {
+		return settingChange{}, err
+	}
+	if len(args) == 0 {
+		return settingChange{setting: setting}, nil
+	}
+
+	values, err := config.ParseSettingValue(setting, args)
+	if err != nil {
+		return settingChange{}, err
+	}
+	return settingChange{setting: setting, values: values}, nil
+}
+
+// applySetting 检查并写入一个配置项，set-mirror、set-keyring 等命令使用，与 svm config set/unset 相同。
+// sdkName 不为空时 key 为SDK配置项，args 为空时恢复默认值
+func applySetting(sdkName, key string, args []string) (*config.Config, config.Setting, []string, error) {
+	if sdkName != "" {
+		if err := checkSDKName(sdkName); err != nil {
+			return nil, config.Setting{}, nil, err
+		}
+		key = sdkName + "." + key
+	}
+
+	change, err := parseSettingChange(key, args)
+	if err != nil {
+		return nil, config.Setting{}, nil, err
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return nil, config.Setting{}, nil, fmt.Errorf("加载配置失败: %w", err)
+	}
+	if err := cfg.SetSetting(change.setting, change.values); err != nil {
+		return nil, config.Setting{}, nil, fmt.Errorf("保存配置失败: %w", err)
+	}
+	return cfg, change.setting, change.values, nil
+}
+
+// warnEnvOverride 环境变量覆盖了配置项时提示修改不会生效
+func warnEnvOverride(cfg *config.Config, setting config.Setting) {
+	if _, source := cfg.GetSetting(setting); source == config.SourceEnv {
+		utils.Log.Warning(fmt.Sprintf("环境变量 %s 覆盖了 %s，配置文件中的值在取消该环境变量后生效", setting.EnvName(), setting.Key))
+	}
+}
+
 func initConfigCmd() {
+	setInstallDirCmd.Flags().Bool("migrate", false, "将已安装的SDK和缓存移动到新目录")
 	configCmd.AddCommand(setInstallDirCmd)
 	configCmd.AddCommand(getInstallDirCmd)
+	configCmd.AddCommand(setKeyringCmd)
+	configCmd.AddCommand(refreshKeyringCmd)
+
+	setMirrorCmd.Flags().Bool("metadata", false, "只设置版本元数据的镜像")
+	setMirrorCmd.Flags().Bool("archives", false, "只设置下载文件的镜像")
+	configCmd.AddCommand(setMirrorCmd)
+	configCmd.AddCommand(getMirrorCmd)
+
+	setNetworkCmd.Flags().StringSlice("ca-cert", nil, "额外信任的根证书文件（PEM格式），可以指定多次，传入空字符串清除")
+	setNetworkCmd.Flags().Int("timeout", 0, "请求超时（秒），0 使用默认值")
+	setNetworkCmd.Flags().Int("retries", utils.DefaultRetries, "服务器返回 5xx 或 429 时的重试次数")
+	setNetworkCmd.Flags().String("user-agent", "", "User-Agent，为空时使用默认值")
+	configCmd.AddCommand(setNetworkCmd)
+	configCmd.AddCommand(setMetadataTTLCmd)
+	configCmd.AddCommand(getConfigCmd)
+	configCmd.AddCommand(setConfigCmd)
+	configCmd.AddCommand(unsetConfigCmd)
+	configCmd.AddCommand(listConfigCmd)
+	configCmd.AddCommand(validateConfigCmd)
 	rootCmd.AddCommand(configCmd)
 }
@@ -11,7 +11,10 @@ func initDotNetCmd() {
 	dotnetCmd := &cobra.Command{
 		Use:   "dotnet",
 		Short: "管理 .NET 版本",
-		Long:  `管理 .NET 的不同版本，包括SDK和各种运行时。`,
+		Long: `管理 .NET 的不同版本，包括SDK和各种运行时。
+
+与其他SDK不同，.NET 没有 link 和 discover 命令：各组件按组件目录分别切换，
+不支持登记系统中已有的 .NET 安装。可以使用 install <version> --from-file 从本地安装包安装。`,
 	}
 
 	// 为每种组件类型创建子命令
@@ -39,7 +42,7 @@ func createComponentCmd(componentType, description string) *cobra.Command {
 		Use:   "list",
 		Short: "列出所有可用的 .NET " + description + " 版本",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return handleDotNetCommand("list", componentType, args)
+			return handleDotNetCommand(cmd, "list", componentType, args)
 		},
 	}
 
@@ -48,20 +51,21 @@ func createComponentCmd(componentType, description string) *cobra.Command {
 	listCmd.Flags().BoolP("all", "a", false, "显示所有版本（不过滤）")
 
 	installCmd := &cobra.Command{
-		Use:   "install <version>",
+		Use:   "install [version]",
 		Short: "安装指定版本的 .NET " + description,
-		Args:  cobra.ExactArgs(1),
+		Args:  cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return handleDotNetCommand("install", componentType, args)
+			return handleDotNetCommand(cmd, "install", componentType, args)
 		},
 	}
+	addInstallSourceFlags(installCmd)
 
 	useCmd := &cobra.Command{
-		Use:   "use <version>",
+		Use:   "use [version]",
 		Short: "切换到指定版本的 .NET " + description,
-		Args:  cobra.ExactArgs(1),
+		Args:  cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return handleDotNetCommand("use", componentType, args)
+			return handleDotNetCommand(cmd, "use", componentType, args)
 		},
 	}
 
@@ -70,7 +74,7 @@ func createComponentCmd(componentType, description string) *cobra.Command {
 		Short: "删除指定版本的 .NET " + description,
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return handleDotNetCommand("remove", componentType, args)
+			return handleDotNetCommand(cmd, "remove", componentType, args)
 		},
 	}
 
@@ -78,7 +82,7 @@ func createComponentCmd(componentType, description string) *cobra.Command {
 		Use:   "current",
 		Short: "显示当前使用的 .NET " + description + " 版本",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return handleDotNetCommand("current", componentType, args)
+			return handleDotNetCommand(cmd, "current", componentType, args)
 		},
 	}
 
@@ -88,7 +92,7 @@ func createComponentCmd(componentType, description string) *cobra.Command {
 }
 
 // 处理.NET命令
-func handleDotNetCommand(action, componentType string, args []string) error {
+func handleDotNetCommand(cmd *cobra.Command, action, componentType string, args []string) error {
 	// 获取SDK实例
 	dotnetSdk := GetSDK("dotnet")
 
@@ -102,28 +106,34 @@ func handleDotNetCommand(action, componentType string, args []string) error {
 	// 根据操作执行相应的功能
 	switch action {
 	case "list":
-		versions, err := dotnetSdk.List()
+		releases, err := dotnetSdk.List()
 		if err != nil {
 			return err
 		}
 
-		if len(versions) == 0 {
+		if len(releases) == 0 {
 			utils.Log.Info(fmt.Sprintf("未找到可用的 .NET %s 版本", getComponentTypeDescription(componentType)))
 			return nil
 		}
 
 		utils.Log.Info(fmt.Sprintf("可用的 .NET %s 版本:", getComponentTypeDescription(componentType)))
-		for _, version := range versions {
-			utils.Log.Custom(utils.IconStar, utils.Green, "", version)
+		for _, release := range releases {
+			utils.Log.Custom(utils.IconStar, utils.Green, "", release.String())
 		}
 
 	case "install":
-		version := args[0]
+		version, err := resolveVersionArg(getDotNetProjectName(componentType), args)
+		if err != nil {
+			return err
+		}
 		utils.Log.Install(fmt.Sprintf("正在安装 .NET %s 版本 %s...", getComponentTypeDescription(componentType), version))
-		return dotnetSdk.Install(version)
+		return runInstall(cmd, dotnetSdk, version)
 
 	case "use":
-		version := args[0]
+		version, err := resolveVersionArg(getDotNetProjectName(componentType), args)
+		if err != nil {
+			return err
+		}
 		return dotnetSdk.Use(version)
 
 	case "remove":
@@ -164,3 +174,11 @@ func getComponentTypeDescription(componentType string) string {
 		return componentType
 	}
 }
+
+// 获取组件在项目版本文件中的名称
+func getDotNetProjectName(componentType string) string {
+	if componentType == "sdk" {
+		return "dotnet"
+	}
+	return "dotnet-" + componentType
+}
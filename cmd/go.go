@@ -3,8 +3,9 @@ package cmd
 import (
 	"fmt"
 	"os"
-	"path/filepath"
+	"strings"
 	"svm/internal/config"
+	"svm/internal/sdk"
 	"svm/internal/utils"
 
 	"github.com/spf13/cobra"
@@ -36,27 +37,23 @@ func initGoCmd() {
 				}
 
 				// 获取Go安装目录
-				installDir := filepath.Join(config.InstallDir, "go")
+				installDir := config.GetSDKInstallDir("go")
 
-				// 检查目录是否存在
-				if _, err := os.Stat(installDir); os.IsNotExist(err) {
-					utils.Log.Info("未找到已安装的 Go 版本")
-					return nil
-				}
-
-				// 读取安装目录中的所有子目录
+				// 读取安装目录中的所有子目录，目录不存在时只列出登记的外部目录
 				entries, err := os.ReadDir(installDir)
-				if err != nil {
+				if err != nil && !os.IsNotExist(err) {
 					return err
 				}
 
 				// 过滤出版本目录
 				var installedVersions []string
 				for _, entry := range entries {
-					if entry.IsDir() {
+					// 以 . 开头的是正在进行的安装使用的临时目录
+					if entry.IsDir() && !strings.HasPrefix(entry.Name(), ".") {
 						installedVersions = append(installedVersions, entry.Name())
 					}
 				}
+				installedVersions = append(installedVersions, linkedVersions(config, "go")...)
 
 				if len(installedVersions) == 0 {
 					utils.Log.Info("未找到已安装的 Go 版本")
@@ -71,25 +68,26 @@ func initGoCmd() {
 
 				utils.Log.Info("已安装的 Go 版本：")
 				for _, version := range installedVersions {
+					label := installedVersionLabel(config, "go", version)
 					if version == currentVersion {
-						utils.Log.Custom(utils.IconHeart, utils.Magenta, "", version+" (当前使用)")
+						utils.Log.Custom(utils.IconHeart, utils.Magenta, "", label+" (当前使用)")
 					} else {
-						utils.Log.Custom(utils.IconStar, utils.Green, "", version)
+						utils.Log.Custom(utils.IconStar, utils.Green, "", label)
 					}
 				}
 				return nil
 			}
 
 			// 显示可用版本
-			var versions []string
+			var releases []sdk.Release
 			var err error
 
 			if all {
 				// 显示所有版本
-				versions, err = goSdk.ListAll()
+				releases, err = goSdk.ListAll()
 			} else {
 				// 显示过滤后的版本
-				versions, err = goSdk.List()
+				releases, err = goSdk.List()
 			}
 
 			if err != nil {
@@ -102,8 +100,8 @@ func initGoCmd() {
 				utils.Log.Info("可用的 Go 版本：")
 			}
 
-			for _, version := range versions {
-				utils.Log.Custom(utils.IconStar, utils.Green, "", version)
+			for _, release := range releases {
+				utils.Log.Custom(utils.IconStar, utils.Green, "", release.String())
 			}
 			return nil
 		},
@@ -117,15 +115,21 @@ func initGoCmd() {
 	goInstallCmd := &cobra.Command{
 		Use:   "install [version]",
 		Short: "安装指定版本的 Go",
-		Args:  cobra.ExactArgs(1),
+		Args:  cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			version := args[0]
+			version, err := resolveVersionArg("go", args)
+			if err != nil {
+				return err
+			}
 			goSdk := GetSDK("go")
 			utils.Log.Install(fmt.Sprintf("正在安装 Go 版本 %s...", version))
-			return goSdk.Install(version)
+			return runInstall(cmd, goSdk, version)
 		},
 	}
 
+	// 添加--from-file、--url和--sha256选项
+	addInstallSourceFlags(goInstallCmd)
+
 	goRemoveCmd := &cobra.Command{
 		Use:   "remove [version]",
 		Short: "删除指定版本的 Go",
@@ -141,9 +145,12 @@ func initGoCmd() {
 	goUseCmd := &cobra.Command{
 		Use:   "use [version]",
 		Short: "切换到指定版本的 Go",
-		Args:  cobra.ExactArgs(1),
+		Args:  cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			version := args[0]
+			version, err := resolveVersionArg("go", args)
+			if err != nil {
+				return err
+			}
 			goSdk := GetSDK("go")
 			utils.Log.Switch(fmt.Sprintf("正在切换到 Go 版本 %s...", version))
 			return goSdk.Use(version)
@@ -171,6 +178,7 @@ func initGoCmd() {
 		},
 	}
 
-	goCmd.AddCommand(goListCmd, goInstallCmd, goRemoveCmd, goUseCmd, goCurrentCmd)
+	goCmd.AddCommand(goListCmd, goInstallCmd, goRemoveCmd, goUseCmd, goCurrentCmd,
+		newLinkCmd("go", "Go"), newDiscoverCmd("go", "Go"))
 	rootCmd.AddCommand(goCmd)
 }
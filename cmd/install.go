@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"fmt"
+	"svm/internal/sdk"
+
+	"github.com/spf13/cobra"
+)
+
+// addInstallSourceFlags 为安装命令添加从本地安装包或自定义地址安装的选项
+func addInstallSourceFlags(cmd *cobra.Command) {
+	cmd.Flags().String("from-file", "", "从本地安装包安装，不访问官方下载地址")
+	cmd.Flags().String("url", "", "从自定义地址下载安装包")
+	cmd.Flags().String("sha256", "", "安装包的SHA-256校验值，与 --from-file 或 --url 一起使用")
+}
+
+// runInstall 安装指定版本，指定了 --from-file 或 --url 时从该安装包安装
+func runInstall(cmd *cobra.Command, sdkInstance sdk.SDK, version string) error {
+	fromFile, _ := cmd.Flags().GetString("from-file")
+	fromURL, _ := cmd.Flags().GetString("url")
+	sha256, _ := cmd.Flags().GetString("sha256")
+
+	if fromFile == "" && fromURL == "" {
+		if sha256 != "" {
+			return fmt.Errorf("--sha256 需要与 --from-file 或 --url 一起使用")
+		}
+		return sdkInstance.Install(version)
+	}
+	if fromFile != "" && fromURL != "" {
+		return fmt.Errorf("--from-file 和 --url 不能同时使用")
+	}
+
+	installer, ok := sdkInstance.(interface {
+		InstallFromArchive(string, sdk.ArchiveSource) error
+	})
+	if !ok {
+		return fmt.Errorf("%s 不支持从指定的安装包安装", sdkInstance.GetName())
+	}
+	return installer.InstallFromArchive(version, sdk.ArchiveSource{
+		File:   fromFile,
+		URL:    fromURL,
+		SHA256: sha256,
+	})
+}
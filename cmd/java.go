@@ -3,8 +3,9 @@ package cmd
 import (
 	"fmt"
 	"os"
-	"path/filepath"
+	"strings"
 	"svm/internal/config"
+	"svm/internal/sdk"
 	"svm/internal/utils"
 
 	"github.com/spf13/cobra"
@@ -36,27 +37,23 @@ func initJavaCmd() {
 				}
 
 				// 获取Java安装目录
-				installDir := filepath.Join(config.InstallDir, "java")
+				installDir := config.GetSDKInstallDir("java")
 
-				// 检查目录是否存在
-				if _, err := os.Stat(installDir); os.IsNotExist(err) {
-					utils.Log.Info("未找到已安装的 Java 版本")
-					return nil
-				}
-
-				// 读取安装目录中的所有子目录
+				// 读取安装目录中的所有子目录，目录不存在时只列出登记的外部目录
 				entries, err := os.ReadDir(installDir)
-				if err != nil {
+				if err != nil && !os.IsNotExist(err) {
 					return err
 				}
 
 				// 过滤出版本目录
 				var installedVersions []string
 				for _, entry := range entries {
-					if entry.IsDir() {
+					// 以 . 开头的是正在进行的安装使用的临时目录
+					if entry.IsDir() && !strings.HasPrefix(entry.Name(), ".") {
 						installedVersions = append(installedVersions, entry.Name())
 					}
 				}
+				installedVersions = append(installedVersions, linkedVersions(config, "java")...)
 
 				if len(installedVersions) == 0 {
 					utils.Log.Info("未找到已安装的 Java 版本")
@@ -71,25 +68,26 @@ func initJavaCmd() {
 
 				utils.Log.Info("已安装的 Java 版本：")
 				for _, version := range installedVersions {
+					label := installedVersionLabel(config, "java", version)
 					if version == currentVersion {
-						utils.Log.Custom(utils.IconHeart, utils.Magenta, "", version+" (当前使用)")
+						utils.Log.Custom(utils.IconHeart, utils.Magenta, "", label+" (当前使用)")
 					} else {
-						utils.Log.Custom(utils.IconStar, utils.Green, "", version)
+						utils.Log.Custom(utils.IconStar, utils.Green, "", label)
 					}
 				}
 				return nil
 			}
 
 			// 显示可用版本
-			var versions []string
+			var releases []sdk.Release
 			var err error
 
 			if all {
 				// 显示所有版本
-				versions, err = javaSdk.ListAll()
+				releases, err = javaSdk.ListAll()
 			} else {
 				// 显示过滤后的版本
-				versions, err = javaSdk.List()
+				releases, err = javaSdk.List()
 			}
 
 			if err != nil {
@@ -102,8 +100,8 @@ func initJavaCmd() {
 				utils.Log.Info("可用的 Java 版本：")
 			}
 
-			for _, version := range versions {
-				utils.Log.Custom(utils.IconStar, utils.Green, "", version)
+			for _, release := range releases {
+				utils.Log.Custom(utils.IconStar, utils.Green, "", release.String())
 			}
 			return nil
 		},
@@ -117,15 +115,21 @@ func initJavaCmd() {
 	javaInstallCmd := &cobra.Command{
 		Use:   "install [version]",
 		Short: "安装指定版本的 Java",
-		Args:  cobra.ExactArgs(1),
+		Args:  cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			version := args[0]
+			version, err := resolveVersionArg("java", args)
+			if err != nil {
+				return err
+			}
 			javaSdk := GetSDK("java")
 			utils.Log.Install(fmt.Sprintf("正在安装 Java 版本 %s...", version))
-			return javaSdk.Install(version)
+			return runInstall(cmd, javaSdk, version)
 		},
 	}
 
+	// 添加--from-file、--url和--sha256选项
+	addInstallSourceFlags(javaInstallCmd)
+
 	javaRemoveCmd := &cobra.Command{
 		Use:   "remove [version]",
 		Short: "删除指定版本的 Java",
@@ -141,9 +145,12 @@ func initJavaCmd() {
 	javaUseCmd := &cobra.Command{
 		Use:   "use [version]",
 		Short: "切换到指定版本的 Java",
-		Args:  cobra.ExactArgs(1),
+		Args:  cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			version := args[0]
+			version, err := resolveVersionArg("java", args)
+			if err != nil {
+				return err
+			}
 			javaSdk := GetSDK("java")
 			utils.Log.Switch(fmt.Sprintf("正在切换到 Java 版本 %s...", version))
 			return javaSdk.Use(version)
@@ -172,6 +179,7 @@ func initJavaCmd() {
 		},
 	}
 
-	javaCmd.AddCommand(javaListCmd, javaInstallCmd, javaRemoveCmd, javaUseCmd, javaCurrentCmd)
+	javaCmd.AddCommand(javaListCmd, javaInstallCmd, javaRemoveCmd, javaUseCmd, javaCurrentCmd,
+		newLinkCmd("java", "Java"), newDiscoverCmd("java", "Java"))
 	rootCmd.AddCommand(javaCmd)
 }
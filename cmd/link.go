@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"svm/internal/config"
+	"svm/internal/sdk"
+	"svm/internal/utils"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// linker 由支持登记外部目录的SDK实现
+type linker interface {
+	Link(name, dir string) error
+	Discover() ([]sdk.Installation, error)
+}
+
+// newLinkCmd 创建将外部目录登记为已安装版本的命令
+func newLinkCmd(sdkName, title string) *cobra.Command {
+	return &cobra.Command{
+		Use:   "link <name> <dir>",
+		Short: fmt.Sprintf("将已有的 %s 目录登记为已安装版本", title),
+		Long: fmt.Sprintf(`将系统中已有的 %s 目录登记为指定名称的已安装版本，之后可以用 use 切换。
+登记的目录不会被修改，remove 时只取消登记，不删除目录。`, title),
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			l, err := sdkLinker(sdkName)
+			if err != nil {
+				return err
+			}
+			return l.Link(args[0], args[1])
+		},
+	}
+}
+
+// newDiscoverCmd 创建在常见位置查找已有安装并登记的命令
+func newDiscoverCmd(sdkName, title string) *cobra.Command {
+	discoverCmd := &cobra.Command{
+		Use:   "discover",
+		Short: fmt.Sprintf("查找系统中已有的 %s 并登记", title),
+		Long: fmt.Sprintf(`在常见位置查找系统中已有的 %s，逐个询问是否登记为已安装版本。
+使用 --yes 登记找到的所有目录；非交互环境中未指定 --yes 时只列出结果。`, title),
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			l, err := sdkLinker(sdkName)
+			if err != nil {
+				return err
+			}
+
+			installations, err := l.Discover()
+			if err != nil {
+				return err
+			}
+			if len(installations) == 0 {
+				utils.Log.Info(fmt.Sprintf("没有找到尚未登记的 %s", title))
+				return nil
+			}
+
+			yes, _ := cmd.Flags().GetBool("yes")
+			interactive := term.IsTerminal(int(os.Stdin.Fd()))
+
+			utils.Log.Search(fmt.Sprintf("找到 %d 个尚未登记的 %s：", len(installations), title))
+			for _, installation := range installations {
+				utils.Log.Custom(utils.IconStar, utils.Green, "", fmt.Sprintf("%s  %s", installation.Name, installation.Dir))
+			}
+			if !yes && !interactive {
+				utils.Log.Info(fmt.Sprintf("使用 svm %s link <name> <dir> 登记，或使用 --yes 登记全部", sdkName))
+				return nil
+			}
+
+			reader := bufio.NewReader(os.Stdin)
+			for _, installation := range installations {
+				if !yes && !confirm(reader, fmt.Sprintf("登记 %s (%s)?", installation.Name, installation.Dir)) {
+					continue
+				}
+				if err := l.Link(installation.Name, installation.Dir); err != nil {
+					utils.Log.Warning(fmt.Sprintf("登记 %s 失败: %v", installation.Dir, err))
+				}
+			}
+			return nil
+		},
+	}
+	discoverCmd.Flags().BoolP("yes", "y", false, "登记找到的所有目录，不逐个询问")
+	return discoverCmd
+}
+
+// sdkLinker 返回支持登记外部目录的SDK实例
+func sdkLinker(sdkName string) (linker, error) {
+	l, ok := GetSDK(sdkName).(linker)
+	if !ok {
+		return nil, fmt.Errorf("%s 不支持登记外部目录", sdkName)
+	}
+	return l, nil
+}
+
+// confirm 询问用户并读取回答，只有 y 或 yes 视为同意
+func confirm(reader *bufio.Reader, question string) bool {
+	fmt.Printf("%s [y/N] ", question)
+	answer, _ := reader.ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
+// linkedVersions 返回配置中登记的外部目录名称
+func linkedVersions(cfg *config.Config, sdkName string) []string {
+	var versions []string
+	for version, info := range cfg.SDKs[sdkName].VersionCache {
+		if info.Linked {
+			versions = append(versions, version)
+		}
+	}
+	return versions
+}
+
+// installedVersionLabel 返回已安装版本的显示名称，登记的外部目录附带目录路径
+func installedVersionLabel(cfg *config.Config, sdkName, version string) string {
+	if info, ok := cfg.GetVersionInfo(sdkName, version); ok && info.Linked {
+		return fmt.Sprintf("%s -> %s", version, info.InstallDir)
+	}
+	return version
+}
@@ -3,8 +3,9 @@ package cmd
 import (
 	"fmt"
 	"os"
-	"path/filepath"
+	"strings"
 	"svm/internal/config"
+	"svm/internal/sdk"
 	"svm/internal/utils"
 
 	"github.com/spf13/cobra"
@@ -36,27 +37,23 @@ func initNodeCmd() {
 				}
 
 				// 获取Node.js安装目录
-				installDir := filepath.Join(config.InstallDir, "node")
+				installDir := config.GetSDKInstallDir("node")
 
-				// 检查目录是否存在
-				if _, err := os.Stat(installDir); os.IsNotExist(err) {
-					utils.Log.Info("未找到已安装的 Node.js 版本")
-					return nil
-				}
-
-				// 读取安装目录中的所有子目录
+				// 读取安装目录中的所有子目录，目录不存在时只列出登记的外部目录
 				entries, err := os.ReadDir(installDir)
-				if err != nil {
+				if err != nil && !os.IsNotExist(err) {
 					return err
 				}
 
 				// 过滤出版本目录
 				var installedVersions []string
 				for _, entry := range entries {
-					if entry.IsDir() {
+					// 以 . 开头的是正在进行的安装使用的临时目录
+					if entry.IsDir() && !strings.HasPrefix(entry.Name(), ".") {
 						installedVersions = append(installedVersions, entry.Name())
 					}
 				}
+				installedVersions = append(installedVersions, linkedVersions(config, "node")...)
 
 				if len(installedVersions) == 0 {
 					utils.Log.Info("未找到已安装的 Node.js 版本")
@@ -71,25 +68,26 @@ func initNodeCmd() {
 
 				utils.Log.Info("已安装的 Node.js 版本：")
 				for _, version := range installedVersions {
+					label := installedVersionLabel(config, "node", version)
 					if version == currentVersion {
-						utils.Log.Custom(utils.IconHeart, utils.Magenta, "", version+" (当前使用)")
+						utils.Log.Custom(utils.IconHeart, utils.Magenta, "", label+" (当前使用)")
 					} else {
-						utils.Log.Custom(utils.IconStar, utils.Green, "", version)
+						utils.Log.Custom(utils.IconStar, utils.Green, "", label)
 					}
 				}
 				return nil
 			}
 
 			// 显示可用版本
-			var versions []string
+			var releases []sdk.Release
 			var err error
 
 			if all {
 				// 显示所有版本
-				versions, err = nodeSdk.ListAll()
+				releases, err = nodeSdk.ListAll()
 			} else {
 				// 显示过滤后的版本
-				versions, err = nodeSdk.List()
+				releases, err = nodeSdk.List()
 			}
 
 			if err != nil {
@@ -102,8 +100,8 @@ func initNodeCmd() {
 				utils.Log.Info("可用的 Node.js 版本：")
 			}
 
-			for _, version := range versions {
-				utils.Log.Custom(utils.IconStar, utils.Green, "", version)
+			for _, release := range releases {
+				utils.Log.Custom(utils.IconStar, utils.Green, "", release.String())
 			}
 			return nil
 		},
@@ -117,15 +115,33 @@ func initNodeCmd() {
 	nodeInstallCmd := &cobra.Command{
 		Use:   "install [version]",
 		Short: "安装指定版本的 Node.js",
-		Args:  cobra.ExactArgs(1),
+		Args:  cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			version := args[0]
+			version, err := resolveVersionArg("node", args)
+			if err != nil {
+				return err
+			}
 			nodeSdk := GetSDK("node")
+
+			// 镜像不发布签名时可以跳过签名验证，仍会校验SHASUMS256.txt中的校验值
+			skipSignature, _ := cmd.Flags().GetBool("skip-signature")
+			if setter, ok := nodeSdk.(interface{ SetSkipSignature(bool) }); ok {
+				setter.SetSkipSignature(skipSignature)
+			}
+			if skipSignature {
+				utils.Log.Warning("已禁用签名验证，SHASUMS256.txt 的真实性将无法保证")
+			}
+
 			utils.Log.Install(fmt.Sprintf("正在安装 Node.js 版本 %s...", version))
-			return nodeSdk.Install(version)
+			return runInstall(cmd, nodeSdk, version)
 		},
 	}
 
+	// 添加--skip-signature选项
+	nodeInstallCmd.Flags().Bool("skip-signature", false, "跳过 SHASUMS256.txt 的签名验证（用于不发布签名的镜像）")
+	// 添加--from-file、--url和--sha256选项
+	addInstallSourceFlags(nodeInstallCmd)
+
 	nodeRemoveCmd := &cobra.Command{
 		Use:   "remove [version]",
 		Short: "删除指定版本的 Node.js",
@@ -141,9 +157,12 @@ func initNodeCmd() {
 	nodeUseCmd := &cobra.Command{
 		Use:   "use [version]",
 		Short: "切换到指定版本的 Node.js",
-		Args:  cobra.ExactArgs(1),
+		Args:  cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			version := args[0]
+			version, err := resolveVersionArg("node", args)
+			if err != nil {
+				return err
+			}
 			nodeSdk := GetSDK("node")
 			utils.Log.Switch(fmt.Sprintf("正在切换到 Node.js 版本 %s...", version))
 			return nodeSdk.Use(version)
@@ -172,6 +191,7 @@ func initNodeCmd() {
 		},
 	}
 
-	nodeCmd.AddCommand(nodeListCmd, nodeInstallCmd, nodeRemoveCmd, nodeUseCmd, nodeCurrentCmd)
+	nodeCmd.AddCommand(nodeListCmd, nodeInstallCmd, nodeRemoveCmd, nodeUseCmd, nodeCurrentCmd,
+		newLinkCmd("node", "Node.js"), newDiscoverCmd("node", "Node.js"))
 	rootCmd.AddCommand(nodeCmd)
 }
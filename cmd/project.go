@@ -0,0 +1,170 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"svm/internal/config"
+	"svm/internal/sdk"
+	"svm/internal/utils"
+
+	"github.com/spf13/cobra"
+)
+
+func initProjectCmd() {
+	installCmd := &cobra.Command{
+		Use:   "install",
+		Short: "安装项目版本文件中列出的所有SDK",
+		Long: `从当前目录开始逐级向上查找项目版本文件（.svmrc 或 .tool-versions），
+安装其中列出的所有SDK版本。`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runProjectCommand("install")
+		},
+	}
+
+	useCmd := &cobra.Command{
+		Use:   "use",
+		Short: "切换到项目版本文件中列出的所有SDK版本",
+		Long: `从当前目录开始逐级向上查找项目版本文件（.svmrc 或 .tool-versions），
+切换到其中列出的所有SDK版本，未安装的版本会自动安装。`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runProjectCommand("use")
+		},
+	}
+
+	rootCmd.AddCommand(installCmd, useCmd)
+}
+
+// runProjectCommand 对项目版本文件中的每个SDK执行安装或切换
+func runProjectCommand(action string) error {
+	project, err := loadProjectFile()
+	if err != nil {
+		return err
+	}
+
+	if len(project.SDKs) == 0 {
+		utils.Log.Warning(fmt.Sprintf("项目版本文件 %s 中没有列出任何SDK", project.Path))
+		return nil
+	}
+
+	utils.Log.Info(fmt.Sprintf("使用项目版本文件: %s", project.Path))
+
+	var errs []error
+	for _, name := range project.SDKs {
+		version := project.Versions[name]
+
+		sdkInstance, err := projectSDK(name)
+		if err != nil {
+			utils.Log.Warning(err.Error())
+			continue
+		}
+
+		switch action {
+		case "install":
+			utils.Log.Install(fmt.Sprintf("正在安装 %s 版本 %s...", name, version))
+			err = sdkInstance.Install(version)
+		case "use":
+			utils.Log.Switch(fmt.Sprintf("正在切换到 %s 版本 %s...", name, version))
+			err = sdkInstance.Use(version)
+		}
+
+		if err != nil {
+			utils.Log.Error(fmt.Sprintf("%s %s 处理失败: %v", name, version, err))
+			errs = append(errs, fmt.Errorf("%s %s: %w", name, version, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// loadProjectFile 从当前工作目录开始查找并加载项目版本文件
+func loadProjectFile() (*config.ProjectFile, error) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("获取当前目录失败: %w", err)
+	}
+
+	path, err := config.FindProjectFile(wd)
+	if err != nil {
+		return nil, err
+	}
+
+	return config.LoadProjectFile(path)
+}
+
+// projectSDK 根据项目版本文件中的名称获取SDK实例
+// .NET 的SDK使用 dotnet，其他组件使用 dotnet-<组件类型>，例如 dotnet-runtime
+func projectSDK(name string) (sdk.SDK, error) {
+	sdkName, componentType := name, ""
+	if strings.HasPrefix(name, "dotnet") {
+		sdkName, componentType = "dotnet", "sdk"
+		if suffix := strings.TrimPrefix(name, "dotnet"); suffix != "" {
+			componentType = strings.TrimPrefix(suffix, "-")
+		}
+	}
+
+	sdkInstance := GetSDK(sdkName)
+	if sdkInstance == nil {
+		return nil, fmt.Errorf("不支持的SDK: %s，已跳过", name)
+	}
+
+	if componentType != "" {
+		if setter, ok := sdkInstance.(interface{ SetComponentType(string) }); ok {
+			setter.SetComponentType(componentType)
+		}
+	}
+
+	return sdkInstance, nil
+}
+
+// resolveVersionArg 返回命令行中指定的版本，未指定时从版本文件中读取
+// 从当前目录开始逐级向上查找，同一目录中生态原生的版本文件（如.nvmrc、go.mod）优先于项目版本文件
+func resolveVersionArg(name string, args []string) (string, error) {
+	if len(args) > 0 {
+		return args[0], nil
+	}
+
+	sdkInstance, err := projectSDK(name)
+	if err != nil {
+		return "", err
+	}
+
+	dir, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("获取当前目录失败: %w", err)
+	}
+
+	for {
+		version, filePath, err := sdkInstance.ResolveVersionFile(dir)
+		if err != nil {
+			return "", err
+		}
+		if filePath != "" {
+			utils.Log.Info(fmt.Sprintf("使用版本文件 %s 中的版本: %s", filePath, version))
+			return version, nil
+		}
+
+		if projectPath, ok := config.ProjectFileInDir(dir); ok {
+			project, err := config.LoadProjectFile(projectPath)
+			if err != nil {
+				return "", err
+			}
+			if version, ok := project.GetVersion(name); ok {
+				utils.Log.Info(fmt.Sprintf("使用项目版本文件 %s 中的版本: %s", projectPath, version))
+				return version, nil
+			}
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+
+	return "", fmt.Errorf("未指定版本，且在当前目录及其上级目录中未找到 %s 的版本文件", name)
+}
@@ -3,8 +3,9 @@ package cmd
 import (
 	"fmt"
 	"os"
-	"path/filepath"
+	"strings"
 	"svm/internal/config"
+	"svm/internal/sdk"
 	"svm/internal/utils"
 
 	"github.com/spf13/cobra"
@@ -36,27 +37,23 @@ func initPythonCmd() {
 				}
 
 				// 获取Python安装目录
-				installDir := filepath.Join(config.InstallDir, "python")
+				installDir := config.GetSDKInstallDir("python")
 
-				// 检查目录是否存在
-				if _, err := os.Stat(installDir); os.IsNotExist(err) {
-					utils.Log.Info("未找到已安装的 Python 版本")
-					return nil
-				}
-
-				// 读取安装目录中的所有子目录
+				// 读取安装目录中的所有子目录，目录不存在时只列出登记的外部目录
 				entries, err := os.ReadDir(installDir)
-				if err != nil {
+				if err != nil && !os.IsNotExist(err) {
 					return err
 				}
 
 				// 过滤出版本目录
 				var installedVersions []string
 				for _, entry := range entries {
-					if entry.IsDir() {
+					// 以 . 开头的是正在进行的安装使用的临时目录
+					if entry.IsDir() && !strings.HasPrefix(entry.Name(), ".") {
 						installedVersions = append(installedVersions, entry.Name())
 					}
 				}
+				installedVersions = append(installedVersions, linkedVersions(config, "python")...)
 
 				if len(installedVersions) == 0 {
 					utils.Log.Info("未找到已安装的 Python 版本")
@@ -71,25 +68,26 @@ func initPythonCmd() {
 
 				utils.Log.Info("已安装的 Python 版本：")
 				for _, version := range installedVersions {
+					label := installedVersionLabel(config, "python", version)
 					if version == currentVersion {
-						utils.Log.Custom(utils.IconHeart, utils.Magenta, "", version+" (当前使用)")
+						utils.Log.Custom(utils.IconHeart, utils.Magenta, "", label+" (当前使用)")
 					} else {
-						utils.Log.Custom(utils.IconStar, utils.Green, "", version)
+						utils.Log.Custom(utils.IconStar, utils.Green, "", label)
 					}
 				}
 				return nil
 			}
 
 			// 显示可用版本
-			var versions []string
+			var releases []sdk.Release
 			var err error
 
 			if all {
 				// 显示所有版本
-				versions, err = pythonSdk.ListAll()
+				releases, err = pythonSdk.ListAll()
 			} else {
 				// 显示过滤后的版本
-				versions, err = pythonSdk.List()
+				releases, err = pythonSdk.List()
 			}
 
 			if err != nil {
@@ -102,8 +100,8 @@ func initPythonCmd() {
 				utils.Log.Info("可用的 Python 版本：")
 			}
 
-			for _, version := range versions {
-				utils.Log.Custom(utils.IconStar, utils.Green, "", version)
+			for _, release := range releases {
+				utils.Log.Custom(utils.IconStar, utils.Green, "", release.String())
 			}
 			return nil
 		},
@@ -117,15 +115,30 @@ func initPythonCmd() {
 	pythonInstallCmd := &cobra.Command{
 		Use:   "install [version]",
 		Short: "安装指定版本的 Python",
-		Args:  cobra.ExactArgs(1),
+		Args:  cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			version := args[0]
+			version, err := resolveVersionArg("python", args)
+			if err != nil {
+				return err
+			}
 			pythonSdk := GetSDK("python")
+
+			// python.org的发布API没有校验值时默认停止安装
+			skipChecksum, _ := cmd.Flags().GetBool("skip-checksum")
+			if setter, ok := pythonSdk.(interface{ SetSkipChecksum(bool) }); ok {
+				setter.SetSkipChecksum(skipChecksum)
+			}
+
 			utils.Log.Install(fmt.Sprintf("正在安装 Python 版本 %s...", version))
-			return pythonSdk.Install(version)
+			return runInstall(cmd, pythonSdk, version)
 		},
 	}
 
+	// 添加--skip-checksum选项
+	pythonInstallCmd.Flags().Bool("skip-checksum", false, "python.org 的发布API没有提供校验值时仍然安装（不校验下载的文件）")
+	// 添加--from-file、--url和--sha256选项
+	addInstallSourceFlags(pythonInstallCmd)
+
 	pythonRemoveCmd := &cobra.Command{
 		Use:   "remove [version]",
 		Short: "删除指定版本的 Python",
@@ -141,9 +154,12 @@ func initPythonCmd() {
 	pythonUseCmd := &cobra.Command{
 		Use:   "use [version]",
 		Short: "切换到指定版本的 Python",
-		Args:  cobra.ExactArgs(1),
+		Args:  cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			version := args[0]
+			version, err := resolveVersionArg("python", args)
+			if err != nil {
+				return err
+			}
 			pythonSdk := GetSDK("python")
 			utils.Log.Switch(fmt.Sprintf("正在切换到 Python 版本 %s...", version))
 			return pythonSdk.Use(version)
@@ -176,5 +192,7 @@ func initPythonCmd() {
 	pythonCmd.AddCommand(pythonRemoveCmd)
 	pythonCmd.AddCommand(pythonUseCmd)
 	pythonCmd.AddCommand(pythonCurrentCmd)
+	pythonCmd.AddCommand(newLinkCmd("python", "Python"))
+	pythonCmd.AddCommand(newDiscoverCmd("python", "Python"))
 	rootCmd.AddCommand(pythonCmd)
 }
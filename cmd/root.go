@@ -1,8 +1,12 @@
 package cmd
 
 import (
+	"fmt"
+	"slices"
+	"svm/internal/config"
 	"svm/internal/sdk"
 	"svm/internal/utils"
+	"time"
 
 	"github.com/spf13/cobra"
 )
@@ -19,23 +23,59 @@ var rootCmd = &cobra.Command{
   ` + utils.FormatCommandExample("svm node remove 10") + `           删除 Node.js 10
   ` + utils.FormatCommandExample("svm go use 1.24.1") + `            切换到 Go 1.24.1
   ` + utils.FormatCommandExample("svm dotnet sdk list") + `          列出所有可用的 .NET SDK 版本
+  ` + utils.FormatCommandExample("svm install") + `                  安装项目版本文件中列出的所有SDK
   ` + utils.FormatCommandExample("svm dotnet asp-core install 7.0.0") + `  安装 ASP.NET Core 7.0.0 运行时`,
 }
 
-// 全局SDK实例的映射
+// sdkFactories 各SDK的构造函数，按注册顺序排列
+var sdkFactories = []struct {
+	name   string
+	create func() sdk.SDK
+}{
+	{"node", sdk.NewNodeSDK},
+	{"go", sdk.NewGoSDK},
+	{"java", sdk.NewJavaSDK},
+	{"python", sdk.NewPythonSDK},
+	{"dotnet", sdk.NewDotNetSDK},
+}
+
+// sdkNames 按注册顺序排列的SDK名称
+var sdkNames = func() []string {
+	names := make([]string, 0, len(sdkFactories))
+	for _, factory := range sdkFactories {
+		names = append(names, factory.name)
+	}
+	return names
+}()
+
+// 已创建的SDK实例，SDK在第一次使用时才创建
 var sdkRegistry = map[string]sdk.SDK{}
 
+// offline 离线模式：只使用缓存的版本列表和安装包
+var offline bool
+
+// configFile 通过 --config 指定的配置文件
+var configFile string
+
 func Execute() error {
 	return rootCmd.Execute()
 }
 
 func init() {
-	// 初始化所有SDK
-	registerSDK("node", sdk.NewNodeSDK())
-	registerSDK("go", sdk.NewGoSDK())
-	registerSDK("java", sdk.NewJavaSDK())
-	registerSDK("python", sdk.NewPythonSDK())
-	registerSDK("dotnet", sdk.NewDotNetSDK())
+	// 全局参数
+	rootCmd.PersistentFlags().BoolVar(&offline, "offline", false, "离线模式，只使用缓存的版本列表和安装包")
+	rootCmd.PersistentFlags().StringVar(&configFile, "config", "", fmt.Sprintf("配置文件路径，默认为 $%s/config.json 或 ~/.svm/config.json", config.HomeEnv))
+
+	// 配置文件的位置在解析参数后才能确定，网络配置在执行命令前初始化，SDK实例在命令使用时才创建
+	cobra.OnInitialize(func() {
+		utils.SetOffline(offline)
+		if configFile != "" {
+			config.SetConfigFile(configFile)
+		}
+
+		// 配置所有网络请求共用的客户端
+		configureHTTP()
+	})
 
 	// 初始化各种命令
 	initNodeCmd()
@@ -44,19 +84,70 @@ func init() {
 	initPythonCmd()
 	initDotNetCmd()
 	initConfigCmd()
+	initCacheCmd()
+	initProjectCmd()
 
 	// 为所有命令添加彩色输出
 	formatCommandHelp(rootCmd)
 }
 
-// registerSDK 注册SDK实例
-func registerSDK(name string, sdkInstance sdk.SDK) {
-	sdkRegistry[name] = sdkInstance
+// configureHTTP 根据配置文件设置网络请求的根证书、超时、重试和User-Agent
+func configureHTTP() {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		utils.Log.Warning(fmt.Sprintf("加载配置失败: %v，将使用默认网络配置", err))
+		return
+	}
+
+	if err := utils.ConfigureHTTP(httpOptions(cfg.Network)); err != nil {
+		utils.Log.Warning(fmt.Sprintf("网络配置无效: %v，将使用默认网络配置", err))
+	}
+}
+
+// httpOptions 将配置文件中的网络配置转换为客户端选项
+func httpOptions(network config.NetworkConfig) utils.HTTPOptions {
+	opts := utils.HTTPOptions{
+		CACertFiles:    network.CACerts,
+		RequestTimeout: utils.DefaultRequestTimeout,
+		Retries:        utils.DefaultRetries,
+		UserAgent:      network.UserAgent,
+	}
+	if network.Timeout > 0 {
+		opts.RequestTimeout = time.Duration(network.Timeout) * time.Second
+	}
+	if network.Retries != nil {
+		opts.Retries = *network.Retries
+	}
+	return opts
 }
 
-// GetSDK 获取指定名称的SDK实例
+// GetSDK 获取指定名称的SDK实例，第一次获取时创建，不支持的SDK返回 nil
 func GetSDK(name string) sdk.SDK {
-	return sdkRegistry[name]
+	if sdkInstance, ok := sdkRegistry[name]; ok {
+		return sdkInstance
+	}
+
+	for _, factory := range sdkFactories {
+		if factory.name == name {
+			sdkInstance := factory.create()
+			sdkRegistry[name] = sdkInstance
+			return sdkInstance
+		}
+	}
+	return nil
+}
+
+// checkSDKName 检查是否是支持的SDK，不创建SDK实例
+func checkSDKName(name string) error {
+	if !slices.Contains(sdkNames, name) {
+		return fmt.Errorf("不支持的SDK: %s", name)
+	}
+	return nil
+}
+
+// resetSDKs 丢弃已创建的SDK实例，之后重新创建，例如安装目录改变后
+func resetSDKs() {
+	clear(sdkRegistry)
 }
 
 // formatCommandHelp 为命令的帮助信息添加彩色输出
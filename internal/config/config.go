@@ -1,7 +1,6 @@
 package config
 
 import (
-	"encoding/json"
 	"os"
 	"path/filepath"
 )
@@ -14,127 +13,211 @@ type EnvVar struct {
 
 // SDKVersionInfo 表示SDK版本信息
 type SDKVersionInfo struct {
-	InstallDir    string `json:"install_dir"`
-	CacheFilePath string `json:"cache_file_path"`
+	InstallDir        string `json:"install_dir"`
+	CacheFilePath     string `json:"cache_file_path"`
+	Checksum          string `json:"checksum"`           // 已校验的下载文件校验值
+	ChecksumAlgorithm string `json:"checksum_algorithm"` // 校验算法，例如 sha256、sha512
+	Linked            bool   `json:"linked,omitempty"`   // 登记的外部目录，删除时只取消登记
+}
+
+// MirrorConfig 表示SDK的镜像地址，每个列表按顺序尝试，为空时使用官方地址
+type MirrorConfig struct {
+	Metadata []string `json:"metadata"` // 版本元数据的镜像地址
+	Archives []string `json:"archives"` // 下载文件的镜像地址
 }
 
 // SDKConfig 表示单个SDK的配置
 type SDKConfig struct {
+	InstallDir     string                    `json:"install_dir,omitempty"` // SDK的安装目录，为空时使用全局安装目录下的 <sdk>
 	CurrentVersion string                    `json:"current_version"`
 	EnvVars        []EnvVar                  `json:"env_vars"`
 	VersionCache   map[string]SDKVersionInfo `json:"version_cache"`
 	Components     map[string]string         `json:"components"` // 组件类型 -> 当前版本
+	Keyring        string                    `json:"keyring"`    // 验证校验文件签名的OpenPGP公钥环，为空时使用默认路径
+	Mirrors        MirrorConfig              `json:"mirrors"`    // 镜像地址
+}
+
+// NetworkConfig 表示网络请求的配置，代理从 HTTPS_PROXY、NO_PROXY 等环境变量读取
+type NetworkConfig struct {
+	CACerts   []string `json:"ca_certs"`   // 额外信任的根证书文件（PEM格式）
+	Timeout   int      `json:"timeout"`    // 请求超时（秒），0 使用默认值
+	Retries   *int     `json:"retries"`    // 服务器返回 5xx 或 429 时的重试次数，未设置时使用默认值
+	UserAgent string   `json:"user_agent"` // User-Agent，为空时使用默认值
+}
+
+// CacheConfig 表示缓存的配置
+type CacheConfig struct {
+	MetadataTTL string `json:"metadata_ttl"` // 版本元数据的缓存有效期，例如 6h、30m，为空时使用默认值
 }
 
 // Config 表示全局配置
 type Config struct {
-	InstallDir      string               `json:"install_dir"`
-	CurrentVersions map[string]string    `json:"current_versions"` // 为向后兼容保留
-	SDKs            map[string]SDKConfig `json:"sdks"`             // 新增SDK配置
+	Version    int                  `json:"version"` // 配置文件格式版本，见 SchemaVersion
+	InstallDir string               `json:"install_dir"`
+	SDKs       map[string]SDKConfig `json:"sdks"`    // SDK配置
+	Network    NetworkConfig        `json:"network"` // 网络请求配置
+	Cache      CacheConfig          `json:"cache"`   // 缓存配置
 }
 
-func GetDefaultInstallDir() string {
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		return filepath.Join(".", ".svm")
+// HomeEnv 指定SVM数据目录的环境变量，用于在CI任务、容器或共享主机上隔离配置和安装目录
+const HomeEnv = "SVM_HOME"
+
+// configFileOverride 由 --config 指定的配置文件路径
+var configFileOverride string
+
+// SetConfigFile 指定配置文件路径，优先于 SVM_HOME 和默认位置。之后的 LoadConfig 从该文件重新读取
+func SetConfigFile(path string) {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
 	}
-	return filepath.Join(homeDir, ".svm")
-}
 
-func LoadConfig() (*Config, error) {
-	configFile := getConfigFilePath()
+	configMu.Lock()
+	defer configMu.Unlock()
+	configFileOverride = path
+	shared = nil
+}
 
-	// 如果配置文件不存在，创建默认配置
-	if _, err := os.Stat(configFile); os.IsNotExist(err) {
-		cfg := &Config{
-			InstallDir:      GetDefaultInstallDir(),
-			CurrentVersions: make(map[string]string),
-			SDKs:            make(map[string]SDKConfig),
+// GetHomeDir 返回SVM数据目录：SVM_HOME 环境变量指定的目录，默认为 ~/.svm
+func GetHomeDir() string {
+	if home := os.Getenv(HomeEnv); home != "" {
+		if abs, err := filepath.Abs(home); err == nil {
+			return abs
 		}
-		return cfg, cfg.Save()
+		return home
 	}
 
-	data, err := os.ReadFile(configFile)
+	homeDir, err := os.UserHomeDir()
 	if err != nil {
-		return nil, err
+		return filepath.Join(".", ".svm")
 	}
+	return filepath.Join(homeDir, ".svm")
+}
 
-	var cfg Config
-	if err := json.Unmarshal(data, &cfg); err != nil {
-		return nil, err
-	}
+// GetDefaultInstallDir 返回默认的安装目录，即配置文件所在的目录
+func GetDefaultInstallDir() string {
+	return filepath.Dir(getConfigFilePath())
+}
 
-	// 确保各种map已初始化
-	if cfg.CurrentVersions == nil {
-		cfg.CurrentVersions = make(map[string]string)
-	}
+// LoadConfig 返回进程内共享的配置，第一次调用时从配置文件读取，配置文件不存在时创建默认配置，
+// 旧格式的配置文件升级到当前版本。所有SDK和命令使用同一个实例，修改通过 Update 写回
+func LoadConfig() (*Config, error) {
+	configMu.Lock()
+	defer configMu.Unlock()
 
-	if cfg.SDKs == nil {
-		cfg.SDKs = make(map[string]SDKConfig)
+	if shared != nil {
+		return shared, nil
 	}
 
-	// 如果InstallDir为空，使用默认值
-	if cfg.InstallDir == "" {
-		cfg.InstallDir = GetDefaultInstallDir()
+	// 读取时持有文件锁，旧格式的配置文件升级后立即写回
+	var cfg *Config
+	err := withFileLock(func() error {
+		var migrated bool
+		var err error
+		cfg, migrated, err = readConfigFile()
+		if err != nil {
+			return err
+		}
+
+		// 如果配置文件不存在，创建默认配置
+		if cfg == nil {
+			cfg = newDefaultConfig()
+			return writeConfigFile(cfg)
+		}
+		if migrated {
+			return writeConfigFile(cfg)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return &cfg, nil
+	// 环境变量覆盖的配置项只在进程内生效
+	cfg.applyEnv()
+	shared = cfg
+	return shared, nil
 }
 
+// Save 在文件锁保护下原子地写入完整的配置，会覆盖其他进程的修改，修改配置应使用 Update。
+// 环境变量覆盖的配置项保留配置文件中的值
 func (c *Config) Save() error {
-	data, err := json.MarshalIndent(c, "", "  ")
-	if err != nil {
-		return err
-	}
+	configMu.Lock()
+	defer configMu.Unlock()
 
-	configFile := getConfigFilePath()
-	configDir := filepath.Dir(configFile)
-
-	// 确保配置目录存在
-	if err := os.MkdirAll(configDir, 0755); err != nil {
-		return err
-	}
+	return withFileLock(func() error {
+		file, _, err := readConfigFile()
+		if err != nil {
+			return err
+		}
+		if file == nil {
+			file = newDefaultConfig()
+		}
 
-	return os.WriteFile(configFile, data, 0644)
+		out, err := c.withoutEnv(file)
+		if err != nil {
+			return err
+		}
+		return writeConfigFile(out)
+	})
 }
 
 func (c *Config) SetInstallDir(dir string) error {
-	c.InstallDir = dir
-	return c.Save()
+	return c.Update(func(cfg *Config) error {
+		cfg.InstallDir = dir
+		return nil
+	})
 }
 
 func (c *Config) GetCurrentVersion(sdk string) string {
-	if sdkConfig, ok := c.SDKs[sdk]; ok && sdkConfig.CurrentVersion != "" {
-		return sdkConfig.CurrentVersion
-	}
-	return c.CurrentVersions[sdk]
+	return c.SDKs[sdk].CurrentVersion
 }
 
 func (c *Config) SetCurrentVersion(sdk, version string) error {
-	// 更新旧的配置
-	c.CurrentVersions[sdk] = version
-
-	// 更新新的配置
-	if _, ok := c.SDKs[sdk]; !ok {
-		c.SDKs[sdk] = SDKConfig{
-			VersionCache: make(map[string]SDKVersionInfo),
+	return c.Update(func(cfg *Config) error {
+		if _, ok := cfg.SDKs[sdk]; !ok {
+			cfg.SDKs[sdk] = SDKConfig{
+				VersionCache: make(map[string]SDKVersionInfo),
+			}
 		}
-	}
 
-	// 创建一个临时变量修改，然后重新赋值
-	sdkConfig := c.SDKs[sdk]
-	sdkConfig.CurrentVersion = version
-	c.SDKs[sdk] = sdkConfig
+		// 创建一个临时变量修改，然后重新赋值
+		sdkConfig := cfg.SDKs[sdk]
+		sdkConfig.CurrentVersion = version
+		cfg.SDKs[sdk] = sdkConfig
 
-	return c.Save()
+		return nil
+	})
 }
 
+// getConfigFilePath 返回配置文件路径：--config 指定的文件，否则为SVM数据目录中的 config.json
 func getConfigFilePath() string {
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		return filepath.Join(".", ".svm", "config.json")
+	if configFileOverride != "" {
+		return configFileOverride
+	}
+	return filepath.Join(GetHomeDir(), "config.json")
+}
+
+// GetConfigFilePath 返回当前使用的配置文件路径
+func GetConfigFilePath() string {
+	return getConfigFilePath()
+}
+
+// GetKeyringPath 返回指定SDK的公钥环路径，未配置时为配置目录下的 keys/<sdk>.asc
+func (c *Config) GetKeyringPath(sdk string) string {
+	if sdkConfig, ok := c.SDKs[sdk]; ok && sdkConfig.Keyring != "" {
+		return sdkConfig.Keyring
 	}
-	return filepath.Join(homeDir, ".svm", "config.json")
+	return GetDefaultKeyringPath(sdk)
+}
+
+// GetDefaultKeyringPath 返回指定SDK的默认公钥环路径
+func GetDefaultKeyringPath(sdk string) string {
+	return filepath.Join(filepath.Dir(getConfigFilePath()), "keys", sdk+".asc")
+}
+
+// GetMirrors 返回指定SDK的镜像地址
+func (c *Config) GetMirrors(sdk string) MirrorConfig {
+	return c.SDKs[sdk].Mirrors
 }
 
 // GetCurrentVersionInfo 获取指定SDK的特定版本信息
@@ -154,31 +237,46 @@ func (c *Config) GetVersionInfo(sdk, version string) (SDKVersionInfo, bool) {
 
 // SetVersionInfo 设置版本信息
 func (c *Config) SetVersionInfo(sdk, version string, info SDKVersionInfo) error {
-	// 确保SDK配置存在
-	if _, ok := c.SDKs[sdk]; !ok {
-		c.SDKs[sdk] = SDKConfig{
-			VersionCache: make(map[string]SDKVersionInfo),
+	return c.Update(func(cfg *Config) error {
+		// 确保SDK配置存在
+		if _, ok := cfg.SDKs[sdk]; !ok {
+			cfg.SDKs[sdk] = SDKConfig{
+				VersionCache: make(map[string]SDKVersionInfo),
+			}
 		}
-	}
 
-	// 创建一个临时变量修改，然后重新赋值
-	sdkConfig := c.SDKs[sdk]
+		// 创建一个临时变量修改，然后重新赋值
+		sdkConfig := cfg.SDKs[sdk]
 
-	// 确保VersionCache已初始化
-	if sdkConfig.VersionCache == nil {
-		sdkConfig.VersionCache = make(map[string]SDKVersionInfo)
-	}
+		// 确保VersionCache已初始化
+		if sdkConfig.VersionCache == nil {
+			sdkConfig.VersionCache = make(map[string]SDKVersionInfo)
+		}
 
-	// 设置版本信息
-	sdkConfig.VersionCache[version] = info
-	c.SDKs[sdk] = sdkConfig
+		// 设置版本信息
+		sdkConfig.VersionCache[version] = info
+		cfg.SDKs[sdk] = sdkConfig
 
-	// 同时更新原有的CurrentVersions（为了向后兼容）
-	if sdkConfig.CurrentVersion != "" {
-		c.CurrentVersions[sdk] = sdkConfig.CurrentVersion
-	}
+		return nil
+	})
+}
 
-	return c.Save()
+// SetComponentVersion 设置SDK组件（例如 .NET 的 sdk、runtime）的当前版本
+func (c *Config) SetComponentVersion(sdk, component, version string) error {
+	return c.Update(func(cfg *Config) error {
+		sdkConfig, ok := cfg.SDKs[sdk]
+		if !ok {
+			sdkConfig = SDKConfig{
+				VersionCache: make(map[string]SDKVersionInfo),
+			}
+		}
+		if sdkConfig.Components == nil {
+			sdkConfig.Components = make(map[string]string)
+		}
+		sdkConfig.Components[component] = version
+		cfg.SDKs[sdk] = sdkConfig
+		return nil
+	})
 }
 
 // GetSDKEnvVars 获取SDK的环境变量
@@ -192,57 +290,111 @@ func (c *Config) GetSDKEnvVars(sdk string) []EnvVar {
 
 // SetSDKEnvVars 设置SDK的环境变量
 func (c *Config) SetSDKEnvVars(sdk string, envVars []EnvVar) error {
-	// 确保SDK配置存在
-	if _, ok := c.SDKs[sdk]; !ok {
-		c.SDKs[sdk] = SDKConfig{
-			VersionCache: make(map[string]SDKVersionInfo),
+	return c.Update(func(cfg *Config) error {
+		// 确保SDK配置存在
+		if _, ok := cfg.SDKs[sdk]; !ok {
+			cfg.SDKs[sdk] = SDKConfig{
+				VersionCache: make(map[string]SDKVersionInfo),
+			}
 		}
-	}
 
-	// 创建一个临时变量修改，然后重新赋值
-	sdkConfig := c.SDKs[sdk]
-	sdkConfig.EnvVars = envVars
-	c.SDKs[sdk] = sdkConfig
+		// 创建一个临时变量修改，然后重新赋值
+		sdkConfig := cfg.SDKs[sdk]
+		sdkConfig.EnvVars = envVars
+		cfg.SDKs[sdk] = sdkConfig
 
-	return c.Save()
+		return nil
+	})
+}
+
+// GetSDKInstallDir 返回SDK的安装目录：SDK单独配置的目录，默认为全局安装目录下的 <sdk>
+func (c *Config) GetSDKInstallDir(sdk string) string {
+	if dir := c.SDKs[sdk].InstallDir; dir != "" {
+		return dir
+	}
+	return c.defaultSDKInstallDir(sdk)
+}
+
+// defaultSDKInstallDir 返回全局安装目录下SDK的目录
+func (c *Config) defaultSDKInstallDir(sdk string) string {
+	if c.InstallDir == "" {
+		return filepath.Join(GetDefaultInstallDir(), sdk)
+	}
+	return filepath.Join(c.InstallDir, sdk)
 }
 
 // GetCacheDir 返回缓存目录路径
 func (c *Config) GetCacheDir() string {
+	if c.InstallDir == "" {
+		return filepath.Join(GetDefaultInstallDir(), "cache")
+	}
 	return filepath.Join(c.InstallDir, "cache")
 }
 
+// GetMetadataCacheDir 返回指定SDK的版本元数据缓存目录
+func (c *Config) GetMetadataCacheDir(sdk string) string {
+	return filepath.Join(c.GetCacheDir(), "metadata", sdk)
+}
+
+// ClearCacheFile 清除指定版本的缓存文件信息，版本也未安装时移除整个版本信息
+func (c *Config) ClearCacheFile(sdk, version string) error {
+	return c.Update(func(cfg *Config) error {
+		sdkConfig, ok := cfg.SDKs[sdk]
+		if !ok || sdkConfig.VersionCache == nil {
+			return nil
+		}
+
+		info, exists := sdkConfig.VersionCache[version]
+		if !exists {
+			return nil
+		}
+
+		if info.InstallDir == "" {
+			delete(sdkConfig.VersionCache, version)
+		} else {
+			info.CacheFilePath = ""
+			info.Checksum = ""
+			info.ChecksumAlgorithm = ""
+			sdkConfig.VersionCache[version] = info
+		}
+		cfg.SDKs[sdk] = sdkConfig
+
+		return nil
+	})
+}
+
 // RemoveVersionInfo 从配置中移除指定SDK的指定版本信息
 func (c *Config) RemoveVersionInfo(sdk, version string) error {
-	// 检查SDK配置是否存在
-	sdkConfig, ok := c.SDKs[sdk]
-	if !ok {
-		// SDK不存在，无需移除
-		return nil
-	}
+	return c.Update(func(cfg *Config) error {
+		// 检查SDK配置是否存在
+		sdkConfig, ok := cfg.SDKs[sdk]
+		if !ok {
+			// SDK不存在，无需移除
+			return nil
+		}
 
-	// 检查VersionCache是否已初始化
-	if sdkConfig.VersionCache == nil {
-		// VersionCache不存在，无需移除
-		return nil
-	}
+		// 检查VersionCache是否已初始化
+		if sdkConfig.VersionCache == nil {
+			// VersionCache不存在，无需移除
+			return nil
+		}
 
-	// 检查版本是否存在
-	if _, exists := sdkConfig.VersionCache[version]; !exists {
-		// 版本不存在，无需移除
-		return nil
-	}
+		// 检查版本是否存在
+		if _, exists := sdkConfig.VersionCache[version]; !exists {
+			// 版本不存在，无需移除
+			return nil
+		}
 
-	// 移除版本信息
-	delete(sdkConfig.VersionCache, version)
-	c.SDKs[sdk] = sdkConfig
+		// 移除版本信息
+		delete(sdkConfig.VersionCache, version)
+		cfg.SDKs[sdk] = sdkConfig
 
-	// 如果当前版本是被移除的版本，清空当前版本
-	if sdkConfig.CurrentVersion == version {
-		sdkConfig.CurrentVersion = ""
-		c.SDKs[sdk] = sdkConfig
-		delete(c.CurrentVersions, sdk)
-	}
+		// 如果当前版本是被移除的版本，清空当前版本
+		if sdkConfig.CurrentVersion == version {
+			sdkConfig.CurrentVersion = ""
+			cfg.SDKs[sdk] = sdkConfig
+		}
 
-	return c.Save()
+		return nil
+	})
 }
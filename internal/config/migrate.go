@@ -0,0 +1,119 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// migration 将配置文件从上一个格式版本升级到下一个版本，直接修改解析后的JSON
+type migration struct {
+	description string
+	apply       func(raw map[string]any) error
+}
+
+// migrations 按顺序排列，migrations[i] 将版本 i 升级到版本 i+1，修改配置格式时在末尾追加
+var migrations = []migration{
+	{"将 current_versions 合并到 sdks.<sdk>.current_version", migrateCurrentVersions},
+	{"将 .NET 版本的安装目录修正为 <组件>/<版本>", migrateDotNetInstallDirs},
+}
+
+// SchemaVersion 当前的配置文件格式版本，没有 version 字段的配置文件为版本 0
+var SchemaVersion = len(migrations)
+
+// migrateConfig 将配置文件内容按顺序升级到当前版本，返回升级后的内容和原来的版本
+func migrateConfig(data []byte) ([]byte, int, error) {
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, 0, err
+	}
+
+	version := 0
+	if v, ok := raw["version"].(float64); ok {
+		version = int(v)
+	}
+	if version > SchemaVersion {
+		return nil, version, fmt.Errorf("配置文件的版本 %d 高于当前svm支持的版本 %d，请升级svm", version, SchemaVersion)
+	}
+	if version == SchemaVersion {
+		return data, version, nil
+	}
+
+	for i := version; i < SchemaVersion; i++ {
+		if err := migrations[i].apply(raw); err != nil {
+			return nil, version, fmt.Errorf("升级配置文件到版本 %d（%s）失败: %w", i+1, migrations[i].description, err)
+		}
+	}
+	raw["version"] = SchemaVersion
+
+	upgraded, err := json.Marshal(raw)
+	if err != nil {
+		return nil, version, err
+	}
+	return upgraded, version, nil
+}
+
+// jsonObject 返回 raw 中的对象，不存在时创建
+func jsonObject(raw map[string]any, key string) map[string]any {
+	obj, ok := raw[key].(map[string]any)
+	if !ok {
+		obj = make(map[string]any)
+		raw[key] = obj
+	}
+	return obj
+}
+
+// migrateCurrentVersions 旧版本同时在顶层的 current_versions 中记录当前版本，
+// 合并到 sdks.<sdk>.current_version 后删除，两处都有时以 sdks 中的为准
+func migrateCurrentVersions(raw map[string]any) error {
+	legacy, _ := raw["current_versions"].(map[string]any)
+	delete(raw, "current_versions")
+	if len(legacy) == 0 {
+		return nil
+	}
+
+	sdks := jsonObject(raw, "sdks")
+	for sdk, value := range legacy {
+		version, _ := value.(string)
+		if version == "" {
+			continue
+		}
+		sdkConfig := jsonObject(sdks, sdk)
+		if current, _ := sdkConfig["current_version"].(string); current == "" {
+			sdkConfig["current_version"] = version
+		}
+	}
+	return nil
+}
+
+// migrateDotNetInstallDirs 旧版本将 .NET 的安装目录记录为 dotnet/<版本>，
+// 实际文件在安装后被移动到 dotnet/<组件>/<版本>，这里改为实际存在的目录
+func migrateDotNetInstallDirs(raw map[string]any) error {
+	sdks, _ := raw["sdks"].(map[string]any)
+	dotnet, _ := sdks["dotnet"].(map[string]any)
+	versionCache, _ := dotnet["version_cache"].(map[string]any)
+
+	for version, value := range versionCache {
+		info, ok := value.(map[string]any)
+		if !ok {
+			continue
+		}
+		dir, _ := info["install_dir"].(string)
+		if dir == "" || filepath.Base(dir) != version {
+			continue
+		}
+		if _, err := os.Stat(dir); err == nil {
+			continue
+		}
+
+		for _, component := range []string{"sdk", "runtime", "asp-core", "desktop"} {
+			candidate := filepath.Join(filepath.Dir(dir), component, version)
+			if _, err := os.Stat(candidate); err == nil {
+				info["install_dir"] = candidate
+				break
+			}
+		}
+	}
+	return nil
+}
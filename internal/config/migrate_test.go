@@ -0,0 +1,102 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestMigrateConfig(t *testing.T) {
+	dotnetDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dotnetDir, "runtime", "8.0.6"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	oldDir := filepath.Join(dotnetDir, "8.0.6")
+	newDir := filepath.Join(dotnetDir, "runtime", "8.0.6")
+	missingDir := filepath.Join(dotnetDir, "9.0.0")
+
+	tests := []struct {
+		name        string
+		input       string
+		want        string
+		fromVersion int
+		wantErr     bool
+	}{
+		{
+			name:        "当前版本不修改",
+			input:       `{"version":2,"install_dir":"/svm","sdks":{}}`,
+			want:        `{"version":2,"install_dir":"/svm","sdks":{}}`,
+			fromVersion: 2,
+		},
+		{
+			name:        "合并 current_versions",
+			input:       `{"install_dir":"/svm","current_versions":{"node":"v18.20.4","go":"1.22.3","java":""},"sdks":{"go":{"current_version":"1.21.0"}}}`,
+			want:        `{"version":2,"install_dir":"/svm","sdks":{"node":{"current_version":"v18.20.4"},"go":{"current_version":"1.21.0"}}}`,
+			fromVersion: 0,
+		},
+		{
+			name:        "没有 sdks 时创建",
+			input:       `{"current_versions":{"node":"v20.11.1"}}`,
+			want:        `{"version":2,"sdks":{"node":{"current_version":"v20.11.1"}}}`,
+			fromVersion: 0,
+		},
+		{
+			name: "修正 .NET 安装目录",
+			input: `{"version":1,"sdks":{"dotnet":{"version_cache":{` +
+				`"8.0.6":{"install_dir":` + jsonString(oldDir) + `},` +
+				`"9.0.0":{"install_dir":` + jsonString(missingDir) + `}}}}}`,
+			want: `{"version":2,"sdks":{"dotnet":{"version_cache":{` +
+				`"8.0.6":{"install_dir":` + jsonString(newDir) + `},` +
+				`"9.0.0":{"install_dir":` + jsonString(missingDir) + `}}}}}`,
+			fromVersion: 1,
+		},
+		{
+			name:        "高于支持的版本",
+			input:       `{"version":99}`,
+			fromVersion: 99,
+			wantErr:     true,
+		},
+		{
+			name:    "无效的JSON",
+			input:   `{"version":`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, fromVersion, err := migrateConfig([]byte(tt.input))
+			if fromVersion != tt.fromVersion {
+				t.Errorf("原版本 = %d，期望 %d", fromVersion, tt.fromVersion)
+			}
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("期望返回错误，实际为 %s", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("migrateConfig() 失败: %v", err)
+			}
+
+			var gotJSON, wantJSON any
+			if err := json.Unmarshal(got, &gotJSON); err != nil {
+				t.Fatal(err)
+			}
+			if err := json.Unmarshal([]byte(tt.want), &wantJSON); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(gotJSON, wantJSON) {
+				t.Errorf("migrateConfig() = %s，期望 %s", got, tt.want)
+			}
+		})
+	}
+}
+
+// jsonString 返回字符串的JSON表示，用于在测试数据中嵌入路径
+func jsonString(s string) string {
+	data, _ := json.Marshal(s)
+	return string(data)
+}
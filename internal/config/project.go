@@ -0,0 +1,124 @@
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ProjectFileNames 项目级版本文件名（按优先级排列）
+var ProjectFileNames = []string{".svmrc", ".tool-versions"}
+
+// projectToolAliases 将其他工具（如asdf）使用的名称映射为svm的SDK名称
+var projectToolAliases = map[string]string{
+	"nodejs":      "node",
+	"golang":      "go",
+	"dotnet-core": "dotnet",
+	"dotnet-sdk":  "dotnet",
+}
+
+// ProjectFile 表示项目级版本文件
+type ProjectFile struct {
+	Path     string            // 文件路径
+	SDKs     []string          // SDK名称，保持文件中的顺序
+	Versions map[string]string // SDK名称 -> 版本
+}
+
+// FindProjectFile 从指定目录开始逐级向上查找项目级版本文件
+func FindProjectFile(startDir string) (string, error) {
+	dir, err := filepath.Abs(startDir)
+	if err != nil {
+		return "", fmt.Errorf("获取绝对路径失败: %w", err)
+	}
+
+	for {
+		if path, ok := ProjectFileInDir(dir); ok {
+			return path, nil
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+
+	return "", fmt.Errorf("未找到项目版本文件（%s）", strings.Join(ProjectFileNames, ", "))
+}
+
+// ProjectFileInDir 检查指定目录中是否存在项目级版本文件（不向上查找）
+func ProjectFileInDir(dir string) (string, bool) {
+	for _, name := range ProjectFileNames {
+		path := filepath.Join(dir, name)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path, true
+		}
+	}
+	return "", false
+}
+
+// LoadProjectFile 解析项目级版本文件
+// 每行格式为 "<sdk> <version>" 或 "<sdk>=<version>"，以#开头的内容为注释
+func LoadProjectFile(path string) (*ProjectFile, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("打开项目版本文件失败: %w", err)
+	}
+	defer file.Close()
+
+	project := &ProjectFile{
+		Path:     path,
+		Versions: make(map[string]string),
+	}
+
+	scanner := bufio.NewScanner(file)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+
+		// 去掉注释
+		line := scanner.Text()
+		if idx := strings.Index(line, "#"); idx >= 0 {
+			line = line[:idx]
+		}
+		line = strings.TrimSpace(strings.Replace(line, "=", " ", 1))
+		if line == "" {
+			continue
+		}
+
+		// .tool-versions 允许为一个工具列出多个候选版本，只取第一个
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("%s 第 %d 行格式错误: %q", path, lineNo, scanner.Text())
+		}
+
+		name := NormalizeProjectSDKName(fields[0])
+		if _, exists := project.Versions[name]; !exists {
+			project.SDKs = append(project.SDKs, name)
+		}
+		project.Versions[name] = fields[1]
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("读取项目版本文件失败: %w", err)
+	}
+
+	return project, nil
+}
+
+// NormalizeProjectSDKName 将项目文件中的工具名称转换为svm的SDK名称
+func NormalizeProjectSDKName(name string) string {
+	name = strings.ToLower(name)
+	if alias, ok := projectToolAliases[name]; ok {
+		return alias
+	}
+	return name
+}
+
+// GetVersion 获取指定SDK在项目文件中的版本
+func (p *ProjectFile) GetVersion(sdk string) (string, bool) {
+	version, ok := p.Versions[sdk]
+	return version, ok
+}
@@ -0,0 +1,506 @@
+package config
+
+import (
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"svm/internal/utils"
+	"sync"
+	"time"
+)
+
+// 配置项的来源
+const (
+	SourceDefault = "default" // 未设置，使用默认值
+	SourceFile    = "file"    // 配置文件
+	SourceEnv     = "env"     // 环境变量
+)
+
+// envPrefix 覆盖配置项的环境变量前缀，例如 SVM_NETWORK_TIMEOUT、SVM_NODE_MIRROR
+const envPrefix = "SVM_"
+
+// settingSpec 描述一种配置项。值统一表示为字符串列表，单值配置项只有一个元素，空列表表示未设置
+type settingSpec struct {
+	key          string // 配置项路径，SDK配置项不含SDK名称
+	description  string
+	sdk          bool // SDK配置项，完整路径为 <sdk>.<key>
+	list         bool // 值是列表，可以用逗号分隔或传入多个值
+	alias        bool // 同时设置其他配置项的快捷方式，list 时不显示
+	get          func(c *Config, sdk string) []string
+	set          func(c *Config, sdk string, values []string)
+	normalize    func(values []string) ([]string, error) // 检查并规范化要设置的值
+	defaultValue func(c *Config, sdk string) string
+}
+
+// settingSpecs 所有配置项，按 list 显示的顺序排列
+var settingSpecs = []*settingSpec{
+	{
+		key:         "install_dir",
+		description: "SDK安装目录",
+		get:         func(c *Config, _ string) []string { return nonEmpty(c.InstallDir) },
+		set: func(c *Config, _ string, values []string) {
+			c.InstallDir = firstValue(values)
+		},
+		normalize:    normalizePaths,
+		defaultValue: func(*Config, string) string { return GetDefaultInstallDir() },
+	},
+	{
+		key:         "network.ca_certs",
+		description: "额外信任的根证书文件（PEM格式）",
+		list:        true,
+		get:         func(c *Config, _ string) []string { return c.Network.CACerts },
+		set: func(c *Config, _ string, values []string) {
+			c.Network.CACerts = values
+		},
+		normalize: func(values []string) ([]string, error) {
+			values, err := normalizePaths(values)
+			if err != nil {
+				return nil, err
+			}
+			for _, file := range values {
+				data, err := os.ReadFile(file)
+				if err != nil {
+					return nil, fmt.Errorf("读取根证书失败: %w", err)
+				}
+				if !x509.NewCertPool().AppendCertsFromPEM(data) {
+					return nil, fmt.Errorf("根证书 %s 中没有有效的PEM证书", file)
+				}
+			}
+			return values, nil
+		},
+		defaultValue: func(*Config, string) string { return "" },
+	},
+	{
+		key:         "network.timeout",
+		description: "请求超时（秒）",
+		get: func(c *Config, _ string) []string {
+			if c.Network.Timeout == 0 {
+				return nil
+			}
+			return []string{strconv.Itoa(c.Network.Timeout)}
+		},
+		set: func(c *Config, _ string, values []string) {
+			c.Network.Timeout, _ = strconv.Atoi(firstValue(values))
+		},
+		normalize: normalizeNonNegativeInt,
+		defaultValue: func(*Config, string) string {
+			return strconv.Itoa(int(utils.DefaultRequestTimeout / time.Second))
+		},
+	},
+	{
+		key:         "network.retries",
+		description: "服务器返回 5xx 或 429 时的重试次数",
+		get: func(c *Config, _ string) []string {
+			if c.Network.Retries == nil {
+				return nil
+			}
+			return []string{strconv.Itoa(*c.Network.Retries)}
+		},
+		set: func(c *Config, _ string, values []string) {
+			c.Network.Retries = nil
+			if len(values) > 0 {
+				retries, _ := strconv.Atoi(values[0])
+				c.Network.Retries = &retries
+			}
+		},
+		normalize:    normalizeNonNegativeInt,
+		defaultValue: func(*Config, string) string { return strconv.Itoa(utils.DefaultRetries) },
+	},
+	{
+		key:         "network.user_agent",
+		description: "请求使用的 User-Agent",
+		get:         func(c *Config, _ string) []string { return nonEmpty(c.Network.UserAgent) },
+		set: func(c *Config, _ string, values []string) {
+			c.Network.UserAgent = firstValue(values)
+		},
+		defaultValue: func(*Config, string) string { return utils.DefaultUserAgent() },
+	},
+	{
+		key:         "cache.metadata_ttl",
+		description: "版本列表等远程元数据的缓存有效期，例如 30m、6h",
+		get:         func(c *Config, _ string) []string { return nonEmpty(c.Cache.MetadataTTL) },
+		set: func(c *Config, _ string, values []string) {
+			c.Cache.MetadataTTL = firstValue(values)
+		},
+		normalize: func(values []string) ([]string, error) {
+			if ttl, err := time.ParseDuration(values[0]); err != nil || ttl < 0 {
+				return nil, fmt.Errorf("无效的有效期 %q，例如 30m、6h", values[0])
+			}
+			return values, nil
+		},
+		defaultValue: func(*Config, string) string { return utils.DefaultMetadataTTL.String() },
+	},
+	{
+		key:         "install_dir",
+		description: "SDK单独使用的安装目录",
+		sdk:         true,
+		get:         func(c *Config, sdk string) []string { return nonEmpty(c.SDKs[sdk].InstallDir) },
+		set: func(c *Config, sdk string, values []string) {
+			c.updateSDK(sdk, func(sdkConfig *SDKConfig) { sdkConfig.InstallDir = firstValue(values) })
+		},
+		normalize:    normalizePaths,
+		defaultValue: func(c *Config, sdk string) string { return c.defaultSDKInstallDir(sdk) },
+	},
+	{
+		key:         "mirror",
+		description: "版本元数据和下载文件的镜像地址",
+		sdk:         true,
+		list:        true,
+		alias:       true,
+		get: func(c *Config, sdk string) []string {
+			mirrors := c.SDKs[sdk].Mirrors
+			if !slices.Equal(mirrors.Metadata, mirrors.Archives) {
+				return nil
+			}
+			return mirrors.Archives
+		},
+		set: func(c *Config, sdk string, values []string) {
+			c.updateSDK(sdk, func(sdkConfig *SDKConfig) {
+				sdkConfig.Mirrors.Metadata = values
+				sdkConfig.Mirrors.Archives = values
+			})
+		},
+		normalize:    normalizeURLs,
+		defaultValue: func(*Config, string) string { return "" },
+	},
+	{
+		key:         "mirror.metadata",
+		description: "版本元数据的镜像地址",
+		sdk:         true,
+		list:        true,
+		get:         func(c *Config, sdk string) []string { return c.SDKs[sdk].Mirrors.Metadata },
+		set: func(c *Config, sdk string, values []string) {
+			c.updateSDK(sdk, func(sdkConfig *SDKConfig) { sdkConfig.Mirrors.Metadata = values })
+		},
+		normalize:    normalizeURLs,
+		defaultValue: func(*Config, string) string { return "" },
+	},
+	{
+		key:         "mirror.archives",
+		description: "下载文件的镜像地址",
+		sdk:         true,
+		list:        true,
+		get:         func(c *Config, sdk string) []string { return c.SDKs[sdk].Mirrors.Archives },
+		set: func(c *Config, sdk string, values []string) {
+			c.updateSDK(sdk, func(sdkConfig *SDKConfig) { sdkConfig.Mirrors.Archives = values })
+		},
+		normalize:    normalizeURLs,
+		defaultValue: func(*Config, string) string { return "" },
+	},
+	{
+		key:         "keyring",
+		description: "验证校验文件签名的OpenPGP公钥环",
+		sdk:         true,
+		get:         func(c *Config, sdk string) []string { return nonEmpty(c.SDKs[sdk].Keyring) },
+		set: func(c *Config, sdk string, values []string) {
+			c.updateSDK(sdk, func(sdkConfig *SDKConfig) { sdkConfig.Keyring = firstValue(values) })
+		},
+		normalize: func(values []string) ([]string, error) {
+			values, err := normalizePaths(values)
+			if err != nil {
+				return nil, err
+			}
+			if _, err := utils.LoadKeyring(values[0]); err != nil {
+				return nil, err
+			}
+			return values, nil
+		},
+		defaultValue: func(_ *Config, sdk string) string { return GetDefaultKeyringPath(sdk) },
+	},
+}
+
+// Setting 表示一个可以通过 svm config get/set/unset 读写的配置项
+type Setting struct {
+	Key         string // 完整的配置项路径，例如 network.timeout、node.mirror
+	SDK         string // SDK配置项所属的SDK，全局配置项为空
+	Description string
+	spec        *settingSpec
+}
+
+// IsList 判断配置项的值是否是列表
+func (s Setting) IsList() bool {
+	return s.spec.list
+}
+
+// EnvName 返回覆盖配置项的环境变量名称，例如 node.mirror 对应 SVM_NODE_MIRROR
+func (s Setting) EnvName() string {
+	return envPrefix + strings.ToUpper(strings.ReplaceAll(s.Key, ".", "_"))
+}
+
+// newSetting 创建配置项，SDK配置项的路径以SDK名称开头
+func newSetting(spec *settingSpec, sdk string) Setting {
+	key := spec.key
+	if spec.sdk {
+		key = sdk + "." + spec.key
+	}
+	return Setting{Key: key, SDK: sdk, Description: spec.description, spec: spec}
+}
+
+// LookupSetting 按路径查找配置项，SDK配置项的路径为 <sdk>.<配置项>，例如 node.mirror
+func LookupSetting(key string) (Setting, error) {
+	for _, spec := range settingSpecs {
+		if !spec.sdk && spec.key == key {
+			return newSetting(spec, ""), nil
+		}
+	}
+	if sdk, rest, ok := strings.Cut(key, "."); ok {
+		for _, spec := range settingSpecs {
+			if spec.sdk && spec.key == rest {
+				return newSetting(spec, sdk), nil
+			}
+		}
+	}
+	return Setting{}, fmt.Errorf("未知的配置项: %s，使用 svm config list 查看所有配置项", key)
+}
+
+// Settings 返回所有配置项，SDK配置项按给定的SDK依次列出
+func Settings(sdks []string) []Setting {
+	var settings []Setting
+	for _, spec := range settingSpecs {
+		if !spec.sdk {
+			settings = append(settings, newSetting(spec, ""))
+		}
+	}
+	for _, sdk := range sdks {
+		for _, spec := range settingSpecs {
+			if spec.sdk && !spec.alias {
+				settings = append(settings, newSetting(spec, sdk))
+			}
+		}
+	}
+	return settings
+}
+
+// ParseSettingValue 检查并规范化要设置的值，列表配置项的每个参数还可以用逗号分隔多个值
+func ParseSettingValue(s Setting, args []string) ([]string, error) {
+	var values []string
+	for _, arg := range args {
+		if !s.spec.list {
+			values = append(values, arg)
+			continue
+		}
+		for _, item := range strings.Split(arg, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				values = append(values, item)
+			}
+		}
+	}
+
+	if !s.spec.list && len(values) != 1 {
+		return nil, fmt.Errorf("%s 只能设置一个值", s.Key)
+	}
+	if len(values) == 0 {
+		return nil, fmt.Errorf("%s 需要至少一个值，恢复默认值请使用 svm config unset %s", s.Key, s.Key)
+	}
+	if s.spec.normalize != nil {
+		return s.spec.normalize(values)
+	}
+	return values, nil
+}
+
+// GetSetting 返回配置项的生效值及来源，未设置时返回默认值
+func (c *Config) GetSetting(s Setting) (value, source string) {
+	values := s.spec.get(c, s.SDK)
+	switch {
+	case s.envOverride():
+		source = SourceEnv
+	case len(values) > 0:
+		source = SourceFile
+	default:
+		// 空字符串表示没有默认值，例如使用官方地址
+		return s.spec.defaultValue(c, s.SDK), SourceDefault
+	}
+	return strings.Join(values, ","), source
+}
+
+// SetSetting 设置配置项并写回配置文件，values 应先经过 ParseSettingValue 检查
+func (c *Config) SetSetting(s Setting, values []string) error {
+	return c.Update(func(cfg *Config) error {
+		s.spec.set(cfg, s.SDK, values)
+		return nil
+	})
+}
+
+// UnsetSetting 从配置文件中移除配置项，恢复默认值
+func (c *Config) UnsetSetting(s Setting) error {
+	return c.SetSetting(s, nil)
+}
+
+// envOverride 判断环境变量是否覆盖了配置项
+func (s Setting) envOverride() bool {
+	for _, override := range envOverrides() {
+		if override.setting.Key == s.Key {
+			return true
+		}
+	}
+	// 快捷方式由具体的配置项覆盖
+	if s.spec.alias {
+		for _, override := range envOverrides() {
+			if override.setting.SDK == s.SDK && strings.HasPrefix(override.setting.Key, s.Key+".") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// envOverride 表示覆盖配置项的环境变量
+type envOverride struct {
+	setting Setting
+	values  []string
+}
+
+var (
+	envOnce   sync.Once
+	envValues []envOverride
+)
+
+// envOverrides 返回环境变量中有效的配置项覆盖，顺序与 settingSpecs 一致，
+// 快捷方式在具体的配置项之前应用。环境变量只在第一次调用时读取
+func envOverrides() []envOverride {
+	envOnce.Do(func() { envValues = readEnvOverrides() })
+	return envValues
+}
+
+// readEnvOverrides 读取环境变量中的配置项覆盖，SDK配置项的SDK名称从环境变量名称中解析
+func readEnvOverrides() []envOverride {
+	var names []string
+	env := make(map[string]string)
+	for _, item := range os.Environ() {
+		name, value, _ := strings.Cut(item, "=")
+		if strings.HasPrefix(name, envPrefix) {
+			names = append(names, name)
+			env[name] = value
+		}
+	}
+	sort.Strings(names)
+
+	var overrides []envOverride
+	for _, spec := range settingSpecs {
+		for _, name := range names {
+			setting, ok := envSetting(spec, name)
+			if !ok {
+				continue
+			}
+			values, err := ParseSettingValue(setting, []string{env[name]})
+			if err != nil {
+				utils.Log.Warning(fmt.Sprintf("忽略环境变量 %s: %v", name, err))
+				continue
+			}
+			overrides = append(overrides, envOverride{setting: setting, values: values})
+		}
+	}
+	return overrides
+}
+
+// envSetting 判断环境变量是否对应配置项
+func envSetting(spec *settingSpec, name string) (Setting, bool) {
+	if !spec.sdk {
+		setting := newSetting(spec, "")
+		return setting, setting.EnvName() == name
+	}
+
+	suffix := "_" + strings.ToUpper(strings.ReplaceAll(spec.key, ".", "_"))
+	sdk, ok := strings.CutSuffix(strings.TrimPrefix(name, envPrefix), suffix)
+	if !ok || sdk == "" || strings.Contains(sdk, "_") {
+		return Setting{}, false
+	}
+	return newSetting(spec, strings.ToLower(sdk)), true
+}
+
+// applyEnv 应用环境变量对配置项的覆盖，覆盖的值只在进程内生效，不会写入配置文件
+func (c *Config) applyEnv() {
+	for _, override := range envOverrides() {
+		override.setting.spec.set(c, override.setting.SDK, override.values)
+	}
+}
+
+// withoutEnv 返回 c 的副本，环境变量覆盖的配置项恢复为 file 中的值，用于写入配置文件
+func (c *Config) withoutEnv(file *Config) (*Config, error) {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+	out := &Config{}
+	if err := json.Unmarshal(data, out); err != nil {
+		return nil, err
+	}
+	if out.SDKs == nil {
+		out.SDKs = make(map[string]SDKConfig)
+	}
+
+	for _, override := range envOverrides() {
+		spec, sdk := override.setting.spec, override.setting.SDK
+		spec.set(out, sdk, spec.get(file, sdk))
+	}
+	return out, nil
+}
+
+// updateSDK 修改SDK的配置，SDK不存在时先创建
+func (c *Config) updateSDK(sdk string, mutate func(sdkConfig *SDKConfig)) {
+	if c.SDKs == nil {
+		c.SDKs = make(map[string]SDKConfig)
+	}
+	sdkConfig, ok := c.SDKs[sdk]
+	if !ok {
+		sdkConfig = SDKConfig{VersionCache: make(map[string]SDKVersionInfo)}
+	}
+	mutate(&sdkConfig)
+	c.SDKs[sdk] = sdkConfig
+}
+
+// normalizePaths 将路径转换为绝对路径
+func normalizePaths(values []string) ([]string, error) {
+	paths := make([]string, 0, len(values))
+	for _, value := range values {
+		path, err := filepath.Abs(value)
+		if err != nil {
+			return nil, fmt.Errorf("获取绝对路径失败: %w", err)
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+// normalizeNonNegativeInt 检查值是非负整数
+func normalizeNonNegativeInt(values []string) ([]string, error) {
+	n, err := strconv.Atoi(values[0])
+	if err != nil || n < 0 {
+		return nil, fmt.Errorf("无效的值 %q，需要非负整数", values[0])
+	}
+	return []string{strconv.Itoa(n)}, nil
+}
+
+// normalizeURLs 检查镜像地址是 http 或 https 地址
+func normalizeURLs(values []string) ([]string, error) {
+	for _, value := range values {
+		parsed, err := url.Parse(value)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return nil, fmt.Errorf("无效的镜像地址: %s", value)
+		}
+	}
+	return values, nil
+}
+
+// nonEmpty 将非空字符串转换为单个元素的列表
+func nonEmpty(value string) []string {
+	if value == "" {
+		return nil
+	}
+	return []string{value}
+}
+
+// firstValue 返回列表的第一个值，空列表返回空字符串
+func firstValue(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
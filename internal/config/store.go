@@ -0,0 +1,173 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"svm/internal/utils"
+	"sync"
+	"time"
+
+	"github.com/gofrs/flock"
+)
+
+// configLockTimeout 等待其他svm进程释放配置文件锁的最长时间
+const configLockTimeout = 30 * time.Second
+
+var (
+	configMu sync.Mutex // 保护进程内对配置的读写，跨进程由配置文件锁保护
+	shared   *Config    // 进程内共享的配置
+)
+
+// Update 在文件锁保护下重新读取配置文件，对最新的配置应用修改并原子地写回，
+// 然后用写入的内容更新 c。其他svm进程在此期间的修改不会被覆盖
+func (c *Config) Update(mutate func(cfg *Config) error) error {
+	configMu.Lock()
+	defer configMu.Unlock()
+
+	return withFileLock(func() error {
+		latest, _, err := readConfigFile()
+		if err != nil {
+			return err
+		}
+		if latest == nil {
+			latest = newDefaultConfig()
+		}
+
+		if err := mutate(latest); err != nil {
+			return err
+		}
+		if err := writeConfigFile(latest); err != nil {
+			return err
+		}
+
+		*c = *latest
+		c.applyEnv()
+		return nil
+	})
+}
+
+// newDefaultConfig 创建默认配置
+func newDefaultConfig() *Config {
+	return &Config{
+		Version:    SchemaVersion,
+		InstallDir: GetDefaultInstallDir(),
+		SDKs:       make(map[string]SDKConfig),
+	}
+}
+
+// readConfigFile 读取配置文件并升级到当前格式版本，文件不存在时返回 nil。
+// 需要升级时先备份原文件，migrated 为 true，调用方负责写回。调用时需持有配置文件锁
+func readConfigFile() (cfg *Config, migrated bool, err error) {
+	configFile := getConfigFilePath()
+	data, err := os.ReadFile(configFile)
+	if os.IsNotExist(err) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	upgraded, fromVersion, err := migrateConfig(data)
+	if err != nil {
+		return nil, false, err
+	}
+	if fromVersion < SchemaVersion {
+		backupFile := fmt.Sprintf("%s.v%d.bak", configFile, fromVersion)
+		if _, err := os.Stat(backupFile); os.IsNotExist(err) {
+			if err := os.WriteFile(backupFile, data, 0644); err != nil {
+				return nil, false, fmt.Errorf("备份配置文件失败: %w", err)
+			}
+		}
+		utils.Log.Config(fmt.Sprintf("配置文件已从版本 %d 升级到 %d，原文件备份为 %s", fromVersion, SchemaVersion, backupFile))
+		// 写回时未知的配置项会丢失，提醒用户到备份中查看
+		if keys, err := unknownConfigKeys(upgraded); err == nil && len(keys) > 0 {
+			utils.Log.Warning(fmt.Sprintf("升级后的配置文件不再包含未知的配置项: %s", strings.Join(keys, ", ")))
+		}
+		migrated = true
+	}
+
+	cfg = &Config{}
+	if err := json.Unmarshal(upgraded, cfg); err != nil {
+		return nil, false, err
+	}
+
+	// 确保各种map已初始化
+	if cfg.SDKs == nil {
+		cfg.SDKs = make(map[string]SDKConfig)
+	}
+
+	// 如果InstallDir为空，使用默认值
+	if cfg.InstallDir == "" {
+		cfg.InstallDir = GetDefaultInstallDir()
+	}
+
+	return cfg, migrated, nil
+}
+
+// writeConfigFile 先写入同目录下的临时文件再重命名，写入中断时不会留下不完整的配置文件
+func writeConfigFile(cfg *Config) error {
+	cfg.Version = SchemaVersion
+	data, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	configFile := getConfigFilePath()
+	configDir := filepath.Dir(configFile)
+
+	// 确保配置目录存在
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		return err
+	}
+
+	tmpFile, err := os.CreateTemp(configDir, filepath.Base(configFile)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("创建临时文件失败: %w", err)
+	}
+	tmpPath := tmpFile.Name()
+	defer os.Remove(tmpPath)
+
+	if _, err := tmpFile.Write(data); err != nil {
+		tmpFile.Close()
+		return fmt.Errorf("写入配置文件失败: %w", err)
+	}
+	if err := tmpFile.Sync(); err != nil {
+		tmpFile.Close()
+		return fmt.Errorf("写入配置文件失败: %w", err)
+	}
+	if err := tmpFile.Close(); err != nil {
+		return fmt.Errorf("写入配置文件失败: %w", err)
+	}
+	if err := os.Chmod(tmpPath, 0644); err != nil {
+		return fmt.Errorf("设置配置文件权限失败: %w", err)
+	}
+
+	return os.Rename(tmpPath, configFile)
+}
+
+// withFileLock 持有配置文件锁执行 fn，锁文件为配置文件旁的 config.json.lock
+func withFileLock(fn func() error) error {
+	configFile := getConfigFilePath()
+	if err := os.MkdirAll(filepath.Dir(configFile), 0755); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), configLockTimeout)
+	defer cancel()
+
+	lock := flock.New(configFile + ".lock")
+	locked, err := lock.TryLockContext(ctx, 50*time.Millisecond)
+	if err != nil && ctx.Err() == nil {
+		return fmt.Errorf("获取配置文件锁失败: %w", err)
+	}
+	if !locked {
+		return fmt.Errorf("等待配置文件锁超时，可能有其他svm进程正在运行: %s", lock.Path())
+	}
+	defer lock.Unlock()
+
+	return fn()
+}
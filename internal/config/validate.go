@@ -0,0 +1,178 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// ValidationIssue 表示配置文件中的一个问题
+type ValidationIssue struct {
+	Key     string // 问题所在的配置项，例如 sdks.node.current_version
+	Message string
+}
+
+// Validate 检查配置文件：未知的配置项、不存在的安装目录和缓存文件、与已安装版本不一致的当前版本
+func (c *Config) Validate() ([]ValidationIssue, error) {
+	data, err := os.ReadFile(getConfigFilePath())
+	if err != nil {
+		return nil, fmt.Errorf("读取配置文件失败: %w", err)
+	}
+	data, _, err = migrateConfig(data)
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := unknownConfigKeys(data)
+	if err != nil {
+		return nil, fmt.Errorf("解析配置文件失败: %w", err)
+	}
+
+	var issues []ValidationIssue
+	for _, key := range keys {
+		issues = append(issues, ValidationIssue{Key: key, Message: "未知的配置项"})
+	}
+
+	for _, sdk := range sortedKeys(c.SDKs) {
+		sdkConfig := c.SDKs[sdk]
+		prefix := "sdks." + sdk
+
+		for _, version := range sortedKeys(sdkConfig.VersionCache) {
+			info := sdkConfig.VersionCache[version]
+			key := fmt.Sprintf("%s.version_cache.%s", prefix, version)
+			if info.InstallDir != "" && !dirExists(info.InstallDir) {
+				issues = append(issues, ValidationIssue{Key: key + ".install_dir", Message: fmt.Sprintf("安装目录不存在: %s", info.InstallDir)})
+			}
+			if info.CacheFilePath != "" && !fileExists(info.CacheFilePath) {
+				issues = append(issues, ValidationIssue{Key: key + ".cache_file_path", Message: fmt.Sprintf("缓存文件不存在: %s", info.CacheFilePath)})
+			}
+		}
+
+		if sdkConfig.CurrentVersion != "" {
+			issues = append(issues, c.checkCurrentVersion(sdk, prefix+".current_version", sdkConfig.CurrentVersion,
+				filepath.Join(c.GetSDKInstallDir(sdk), "current"))...)
+		}
+		for _, component := range sortedKeys(sdkConfig.Components) {
+			if version := sdkConfig.Components[component]; version != "" {
+				issues = append(issues, c.checkCurrentVersion(sdk, prefix+".components."+component, version,
+					filepath.Join(c.GetSDKInstallDir(sdk), component, "current"))...)
+			}
+		}
+	}
+	return issues, nil
+}
+
+// checkCurrentVersion 检查当前版本已安装，且 current 链接指向该版本的安装目录
+func (c *Config) checkCurrentVersion(sdk, key, version, currentLink string) []ValidationIssue {
+	info, ok := c.GetVersionInfo(sdk, version)
+	if !ok || info.InstallDir == "" {
+		return []ValidationIssue{{Key: key, Message: fmt.Sprintf("当前版本 %s 没有安装记录", version)}}
+	}
+
+	// Windows上 current 可能是目录连接或复制的目录，只检查符号链接
+	target, err := os.Readlink(currentLink)
+	if err != nil {
+		return nil
+	}
+	if !filepath.IsAbs(target) {
+		target = filepath.Join(filepath.Dir(currentLink), target)
+	}
+	if filepath.Clean(target) != filepath.Clean(info.InstallDir) {
+		return []ValidationIssue{{Key: key, Message: fmt.Sprintf("当前版本为 %s，但 %s 指向 %s", version, currentLink, target)}}
+	}
+	return nil
+}
+
+// unknownConfigKeys 返回配置文件内容中 Config 没有对应字段的配置项
+func unknownConfigKeys(data []byte) ([]string, error) {
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	return unknownKeys(raw, reflect.TypeOf(Config{}), ""), nil
+}
+
+// unknownKeys 按结构体的 json 标签找出 value 中没有对应字段的键
+func unknownKeys(value any, t reflect.Type, path string) []string {
+	var keys []string
+	switch t.Kind() {
+	case reflect.Pointer:
+		return unknownKeys(value, t.Elem(), path)
+	case reflect.Struct:
+		obj, ok := value.(map[string]any)
+		if !ok {
+			return nil
+		}
+		fields := make(map[string]reflect.Type)
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if name == "-" {
+				continue
+			}
+			if name == "" {
+				name = field.Name
+			}
+			fields[name] = field.Type
+		}
+		for _, key := range sortedKeys(obj) {
+			fieldType, ok := fields[key]
+			if !ok {
+				keys = append(keys, joinKey(path, key))
+				continue
+			}
+			keys = append(keys, unknownKeys(obj[key], fieldType, joinKey(path, key))...)
+		}
+	case reflect.Map:
+		obj, ok := value.(map[string]any)
+		if !ok {
+			return nil
+		}
+		for _, key := range sortedKeys(obj) {
+			keys = append(keys, unknownKeys(obj[key], t.Elem(), joinKey(path, key))...)
+		}
+	case reflect.Slice:
+		items, ok := value.([]any)
+		if !ok {
+			return nil
+		}
+		for i, item := range items {
+			keys = append(keys, unknownKeys(item, t.Elem(), fmt.Sprintf("%s[%d]", path, i))...)
+		}
+	}
+	return keys
+}
+
+// joinKey 拼接配置项路径
+func joinKey(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// sortedKeys 返回排序后的键
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// dirExists 判断目录是否存在
+func dirExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+// fileExists 判断文件是否存在
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
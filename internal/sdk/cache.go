@@ -0,0 +1,213 @@
+package sdk
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"svm/internal/config"
+	"svm/internal/utils"
+	"time"
+)
+
+// CacheKind 表示缓存文件的类型
+type CacheKind string
+
+const (
+	CacheArchive  CacheKind = "archive"  // 已下载并校验的安装包
+	CachePartial  CacheKind = "partial"  // 未完成的下载，可以断点续传
+	CacheMetadata CacheKind = "metadata" // 版本列表等远程元数据
+)
+
+// metadataCacheDir 缓存目录下存放版本元数据的子目录名
+const metadataCacheDir = "metadata"
+
+// CacheFile 表示缓存目录中的一个文件
+type CacheFile struct {
+	SDK       string    // SDK名称
+	Version   string    // 对应的版本，配置中没有记录时为空
+	Path      string    // 文件路径
+	Size      int64     // 文件大小，未完成的下载包含其续传信息文件
+	ModTime   time.Time // 修改时间
+	Kind      CacheKind // 文件类型
+	Installed bool      // 对应的版本是否已安装
+}
+
+// ListCacheFiles 列出缓存目录中的文件，sdkName 为空时列出所有SDK，按SDK和修改时间排序
+func ListCacheFiles(cfg *config.Config, sdkName string) ([]CacheFile, error) {
+	cacheDir := cfg.GetCacheDir()
+	entries, err := os.ReadDir(cacheDir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("读取缓存目录失败: %w", err)
+	}
+
+	var files []CacheFile
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		if entry.Name() == metadataCacheDir {
+			metadata, err := listMetadataCacheFiles(filepath.Join(cacheDir, metadataCacheDir), sdkName)
+			if err != nil {
+				return nil, err
+			}
+			files = append(files, metadata...)
+			continue
+		}
+
+		if sdkName != "" && entry.Name() != sdkName {
+			continue
+		}
+		archives, err := listArchiveCacheFiles(cfg, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, archives...)
+	}
+
+	sort.SliceStable(files, func(i, j int) bool {
+		if files[i].SDK != files[j].SDK {
+			return files[i].SDK < files[j].SDK
+		}
+		return files[i].ModTime.Before(files[j].ModTime)
+	})
+	return files, nil
+}
+
+// listArchiveCacheFiles 列出指定SDK下载的安装包及未完成的下载
+func listArchiveCacheFiles(cfg *config.Config, sdkName string) ([]CacheFile, error) {
+	dir := filepath.Join(cfg.GetCacheDir(), sdkName)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("读取缓存目录失败: %w", err)
+	}
+
+	// 缓存文件路径 -> 版本
+	versions := make(map[string]string)
+	for version, info := range cfg.SDKs[sdkName].VersionCache {
+		if info.CacheFilePath != "" {
+			versions[filepath.Clean(info.CacheFilePath)] = version
+		}
+	}
+
+	var files []CacheFile
+	for _, entry := range entries {
+		name := entry.Name()
+		// 续传信息文件计入对应的 .part 文件
+		if entry.IsDir() || strings.HasSuffix(name, utils.PartSuffix+".meta") {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+
+		file := CacheFile{
+			SDK:     sdkName,
+			Path:    filepath.Join(dir, name),
+			Size:    info.Size(),
+			ModTime: info.ModTime(),
+			Kind:    CacheArchive,
+		}
+		if strings.HasSuffix(name, utils.PartSuffix) {
+			file.Kind = CachePartial
+			if meta, err := os.Stat(file.Path + ".meta"); err == nil {
+				file.Size += meta.Size()
+			}
+		} else if version, ok := versions[file.Path]; ok {
+			file.Version = version
+			file.Installed = isVersionInstalled(cfg, sdkName, version)
+		}
+		files = append(files, file)
+	}
+	return files, nil
+}
+
+// listMetadataCacheFiles 列出版本元数据的缓存文件
+func listMetadataCacheFiles(dir, sdkName string) ([]CacheFile, error) {
+	var files []CacheFile
+	err := filepath.WalkDir(dir, func(path string, entry os.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+
+		rel, _ := filepath.Rel(dir, path)
+		name, _, _ := strings.Cut(filepath.ToSlash(rel), "/")
+		if sdkName != "" && name != sdkName {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return nil
+		}
+		files = append(files, CacheFile{
+			SDK:     name,
+			Path:    path,
+			Size:    info.Size(),
+			ModTime: info.ModTime(),
+			Kind:    CacheMetadata,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("读取元数据缓存失败: %w", err)
+	}
+	return files, nil
+}
+
+// isVersionInstalled 判断配置中记录的版本是否已安装
+func isVersionInstalled(cfg *config.Config, sdkName, version string) bool {
+	info, exists := cfg.GetVersionInfo(sdkName, version)
+	if !exists || info.InstallDir == "" {
+		return false
+	}
+	exists, _ = utils.CheckDirExists(info.InstallDir)
+	return exists
+}
+
+// RemoveCacheFile 删除缓存文件，并清除配置中对应版本的缓存文件信息
+func RemoveCacheFile(cfg *config.Config, file CacheFile) error {
+	if file.Kind == CachePartial {
+		utils.RemovePartFile(file.Path)
+	} else if err := os.Remove(file.Path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("删除缓存文件失败: %w", err)
+	}
+
+	if file.Version != "" {
+		if err := cfg.ClearCacheFile(file.SDK, file.Version); err != nil {
+			return fmt.Errorf("更新配置失败: %w", err)
+		}
+	}
+	return nil
+}
+
+// ReconcileCacheEntries 清除配置中指向已不存在文件的缓存信息，返回清除的数量
+func ReconcileCacheEntries(cfg *config.Config) (int, error) {
+	type staleEntry struct{ sdk, version string }
+
+	var stale []staleEntry
+	for sdkName, sdkConfig := range cfg.SDKs {
+		for version, info := range sdkConfig.VersionCache {
+			if info.CacheFilePath == "" {
+				continue
+			}
+			if _, err := os.Stat(info.CacheFilePath); os.IsNotExist(err) {
+				stale = append(stale, staleEntry{sdkName, version})
+			}
+		}
+	}
+
+	for _, entry := range stale {
+		if err := cfg.ClearCacheFile(entry.sdk, entry.version); err != nil {
+			return 0, fmt.Errorf("更新配置失败: %w", err)
+		}
+	}
+	return len(stale), nil
+}
@@ -10,6 +10,7 @@ import (
 	"strings"
 	"svm/internal/config"
 	"svm/internal/utils"
+	"sync"
 )
 
 // DotNetReleasesIndex 表示.NET版本索引信息
@@ -41,6 +42,7 @@ type DotNetReleaseDetail struct {
 	ReleaseVersion string                `json:"release-version"`
 	ChannelVersion string                `json:"channel-version"`
 	ReleaseDate    string                `json:"release-date"`
+	Security       bool                  `json:"security"`
 	Runtime        DotNetComponentInfo   `json:"runtime"`
 	SDK            DotNetComponentInfo   `json:"sdk"`
 	AspNetCore     DotNetComponentInfo   `json:"aspnetcore-runtime"`
@@ -65,8 +67,28 @@ type DotNetComponentFile struct {
 
 // DotNetSDKProvider 实现了SDKProvider接口
 type DotNetSDKProvider struct {
-	config        *config.Config
-	componentType string // 组件类型：sdk, runtime, asp-core, desktop
+	config          *config.Config
+	componentType   string                           // 组件类型：sdk, runtime, asp-core, desktop
+	channels        []DotNetReleaseInfo              // 已获取的发布通道
+	channelReleases map[string][]DotNetReleaseDetail // releases.json URL -> 版本详细信息
+	channelMu       sync.Mutex                       // 保护 channelReleases，各发布通道并发获取
+	mirrors         Mirrors
+}
+
+// dotnetcli 存储的地址及其别名，版本元数据位于 release-metadata 下，
+// 安装包位于 Sdk/<版本>、Runtime/<版本> 等目录下
+var dotnetCLIURLs = []string{
+	"https://dotnetcli.blob.core.windows.net/dotnet",
+	"https://builds.dotnet.microsoft.com/dotnet",
+	"https://dotnetcli.azureedge.net/dotnet",
+}
+
+// dotnetArchiveDirs 各组件的安装包在 dotnetcli 存储中的目录
+var dotnetArchiveDirs = map[string]string{
+	"sdk":      "Sdk",
+	"runtime":  "Runtime",
+	"asp-core": "aspnetcore/Runtime",
+	"desktop":  "WindowsDesktop",
 }
 
 // NewDotNetSDK 创建一个新的.NET SDK
@@ -74,6 +96,10 @@ func NewDotNetSDK() SDK {
 	provider := &DotNetSDKProvider{
 		config:        nil,   // 这里为空，会由BaseSDK初始化时设置
 		componentType: "sdk", // 默认为SDK
+		mirrors: Mirrors{
+			Metadata: NewMirror(dotnetCLIURLs...),
+			Archives: NewMirror(dotnetCLIURLs...),
+		},
 	}
 
 	return &dotNetSDK{
@@ -231,23 +257,7 @@ func (s *dotNetSDK) SetupEnv(version string) error {
 	}
 
 	// 保存当前版本到配置文件
-	sdkConfig, exists := s.Config.SDKs[s.GetName()]
-	if !exists {
-		sdkConfig = config.SDKConfig{
-			Components:   make(map[string]string),
-			VersionCache: make(map[string]config.SDKVersionInfo),
-		}
-	}
-
-	// 更新组件当前版本
-	if sdkConfig.Components == nil {
-		sdkConfig.Components = make(map[string]string)
-	}
-	sdkConfig.Components[provider.componentType] = version
-
-	// 保存配置
-	s.Config.SDKs[s.GetName()] = sdkConfig
-	if err := s.Config.Save(); err != nil {
+	if err := s.Config.SetComponentVersion(s.GetName(), provider.componentType, version); err != nil {
 		return fmt.Errorf("保存配置失败: %w", err)
 	}
 
@@ -268,6 +278,16 @@ func (s *dotNetSDK) Use(version string) error {
 	utils.Log.Check(fmt.Sprintf("检查版本目录: %s", versionDir))
 
 	exists, err := utils.CheckDirExists(versionDir)
+	if err != nil || !exists {
+		// 版本号可能不完整（例如来自global.json的8.0），先解析为完整版本
+		if fullVersion, err := s.getLatestMatchingVersion(version); err == nil && fullVersion != version {
+			utils.Log.Info(fmt.Sprintf("版本 %s 解析为 %s", version, fullVersion))
+			version = fullVersion
+			versionDir = filepath.Join(s.InstallDir, provider.componentType, version)
+			exists, err = utils.CheckDirExists(versionDir)
+		}
+	}
+
 	if err != nil || !exists {
 		utils.Log.Warning(fmt.Sprintf("版本目录不存在: %s", versionDir))
 		utils.Log.Install(fmt.Sprintf("%s %s版本 %s 未安装，正在自动安装...", s.Name, provider.componentType, version))
@@ -292,23 +312,7 @@ func (s *dotNetSDK) Use(version string) error {
 	}
 
 	// 更新配置
-	sdkConfig, exists := s.Config.SDKs[s.GetName()]
-	if !exists {
-		sdkConfig = config.SDKConfig{
-			Components:   make(map[string]string),
-			VersionCache: make(map[string]config.SDKVersionInfo),
-		}
-	}
-
-	// 更新组件当前版本
-	if sdkConfig.Components == nil {
-		sdkConfig.Components = make(map[string]string)
-	}
-	sdkConfig.Components[provider.componentType] = version
-
-	// 保存配置
-	s.Config.SDKs[s.GetName()] = sdkConfig
-	if err := s.Config.Save(); err != nil {
+	if err := s.Config.SetComponentVersion(s.GetName(), provider.componentType, version); err != nil {
 		return fmt.Errorf("保存配置失败: %w", err)
 	}
 
@@ -318,8 +322,12 @@ func (s *dotNetSDK) Use(version string) error {
 
 // 获取微软官方版本列表
 func (p *DotNetSDKProvider) getOfficialVersions() ([]DotNetReleaseInfo, error) {
+	if p.channels != nil {
+		return p.channels, nil
+	}
+
 	// 获取版本索引
-	data, err := utils.FetchJSON("https://dotnetcli.blob.core.windows.net/dotnet/release-metadata/releases-index.json")
+	data, err := p.mirrors.Metadata.Fetch(p.mirrors.Metadata.Upstream() + "/release-metadata/releases-index.json")
 	if err != nil {
 		return nil, fmt.Errorf("获取版本列表失败: %w", err)
 	}
@@ -337,119 +345,191 @@ func (p *DotNetSDKProvider) getOfficialVersions() ([]DotNetReleaseInfo, error) {
 		}
 	}
 
+	p.channels = filteredReleases
 	return filteredReleases, nil
 }
 
-// 获取微软所有官方版本列表
-func (p *DotNetSDKProvider) getAllOfficialVersions() ([]DotNetReleaseDetail, error) {
-	// 获取官方版本列表
-	releases, err := p.getOfficialVersions()
+// 获取指定发布通道的所有版本详细信息
+func (p *DotNetSDKProvider) getChannelReleases(channel DotNetReleaseInfo) ([]DotNetReleaseDetail, error) {
+	p.channelMu.Lock()
+	releases, ok := p.channelReleases[channel.ReleasesJSON]
+	p.channelMu.Unlock()
+	if ok {
+		return releases, nil
+	}
+
+	data, err := p.mirrors.Metadata.Fetch(channel.ReleasesJSON)
 	if err != nil {
 		return nil, err
 	}
 
-	// 整理出releases.json URL
-	var releasesJSONURLs []string
-	for _, release := range releases {
-		releasesJSONURLs = append(releasesJSONURLs, release.ReleasesJSON)
+	var releasesJSON DotNetReleasesJSON
+	if err := json.Unmarshal(data, &releasesJSON); err != nil {
+		return nil, err
 	}
 
-	// 获取所有releases.json数据
-	var allReleases []DotNetReleaseDetail
-	for _, url := range releasesJSONURLs {
-		data, err := utils.FetchJSON(url)
-		if err != nil {
-			utils.Log.Warning(fmt.Sprintf("获取 %s 失败: %v", url, err))
-			continue
-		}
-
-		var releasesJSON DotNetReleasesJSON
-		if err := json.Unmarshal(data, &releasesJSON); err != nil {
-			utils.Log.Warning(fmt.Sprintf("解析 %s 失败: %v", url, err))
-			continue
-		}
-
-		// 添加到总列表
-		allReleases = append(allReleases, releasesJSON.Releases...)
+	p.channelMu.Lock()
+	defer p.channelMu.Unlock()
+	if p.channelReleases == nil {
+		p.channelReleases = make(map[string][]DotNetReleaseDetail)
 	}
-
-	return allReleases, nil
+	p.channelReleases[channel.ReleasesJSON] = releasesJSON.Releases
+	return releasesJSON.Releases, nil
 }
 
-// GetVersionList 实现SDKProvider接口，获取所有可用的.NET版本
-func (p *DotNetSDKProvider) GetVersionList() ([]string, error) {
+// GetReleases 实现SDKProvider接口，获取每个发布通道的最新.NET版本
+func (p *DotNetSDKProvider) GetReleases() ([]Release, error) {
 	// 获取官方版本列表
-	releases, err := p.getOfficialVersions()
+	channels, err := p.getOfficialVersions()
 	if err != nil {
 		return nil, err
 	}
 
-	// 根据组件类型返回不同的版本列表
-	var versions []string
-	for _, release := range releases {
-		versions = append(versions, release.LatestRelease)
+	var releases []Release
+	for _, channel := range channels {
+		release := newDotNetRelease(channel)
+		release.Version = channel.LatestRelease
+		release.Date = channel.LatestReleaseDate
+		release.Security = channel.Security
+		releases = append(releases, release)
 	}
 
-	return versions, nil
+	// 按版本号排序（从新到旧）
+	SortReleasesDesc(releases)
+
+	return releases, nil
 }
 
-// GetAllVersionList 实现SDKProvider接口，获取所有可用的.NET版本（不过滤）
-func (p *DotNetSDKProvider) GetAllVersionList() ([]string, error) {
-	// 获取所有官方版本列表
-	releases, err := p.getAllOfficialVersions()
+// GetAllReleases 实现SDKProvider接口，获取所有可用的.NET版本（不过滤）
+func (p *DotNetSDKProvider) GetAllReleases() ([]Release, error) {
+	// 获取官方版本列表
+	channels, err := p.getOfficialVersions()
 	if err != nil {
 		return nil, err
 	}
 
-	// 根据组件类型返回不同的版本列表
-	var versions []string
-	for _, release := range releases {
-		versions = append(versions, release.ReleaseVersion)
+	// 并发获取各发布通道的版本详细信息
+	channelDetails := make([][]DotNetReleaseDetail, len(channels))
+	channelErrs := make([]error, len(channels))
+	var wg sync.WaitGroup
+	for i, channel := range channels {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			channelDetails[i], channelErrs[i] = p.getChannelReleases(channel)
+		}()
 	}
+	wg.Wait()
 
-	return versions, nil
-}
-
-// GetDownloadURL 实现SDKProvider接口，获取下载URL
-func (p *DotNetSDKProvider) GetDownloadURL(version, osName, arch string) string {
-	// 获取所有官方版本列表
-	releases, err := p.getAllOfficialVersions()
-	if err != nil {
-		utils.Log.Error(fmt.Sprintf("获取版本列表失败: %v", err))
-		return ""
-	}
+	var releases []Release
+	for i, channel := range channels {
+		if err := channelErrs[i]; err != nil {
+			utils.Log.Warning(fmt.Sprintf("获取 %s 失败: %v", channel.ReleasesJSON, err))
+			continue
+		}
 
-	// 查找匹配的版本
-	var targetRelease *DotNetReleaseDetail
-	for i, release := range releases {
-		if release.ReleaseVersion == version {
-			targetRelease = &releases[i]
-			break
+		for _, detail := range channelDetails[i] {
+			release := newDotNetRelease(channel)
+			release.Version = detail.ReleaseVersion
+			release.Date = detail.ReleaseDate
+			release.Security = detail.Security
+			files, componentVersion := p.componentFiles(detail)
+			for _, file := range files {
+				releaseFile := newDotNetReleaseFile(file)
+				releaseFile.URL = p.archiveURL(releaseFile, componentVersion)
+				release.Files = append(release.Files, releaseFile)
+			}
+			releases = append(releases, release)
 		}
 	}
 
-	if targetRelease == nil {
-		utils.Log.Warning(fmt.Sprintf("未找到版本 %s", version))
-		return ""
+	// 按版本号排序（从新到旧）
+	SortReleasesDesc(releases)
+
+	return releases, nil
+}
+
+// newDotNetRelease 根据发布通道信息创建版本，填充支持阶段和LTS标记
+func newDotNetRelease(channel DotNetReleaseInfo) Release {
+	release := Release{SupportPhase: channel.SupportPhase}
+	if channel.ReleaseType == "lts" {
+		release.LTS = "LTS"
 	}
+	return release
+}
 
-	// 根据组件类型获取对应的文件
-	var files []DotNetComponentFile
+// componentFiles 获取当前组件类型对应的文件及组件版本号
+func (p *DotNetSDKProvider) componentFiles(detail DotNetReleaseDetail) ([]DotNetComponentFile, string) {
+	var component DotNetComponentInfo
 	switch p.componentType {
 	case "sdk":
-		files = targetRelease.SDK.Files
+		component = detail.SDK
 	case "runtime":
-		files = targetRelease.Runtime.Files
+		component = detail.Runtime
 	case "asp-core":
-		files = targetRelease.AspNetCore.Files
+		component = detail.AspNetCore
 	case "desktop":
-		files = targetRelease.WindowsDesktop.Files
+		component = detail.WindowsDesktop
 	}
 
 	// 如果组件没有单独的Files字段，则使用总的Files字段
-	if len(files) == 0 {
-		files = targetRelease.Files
+	if len(component.Files) == 0 {
+		return detail.Files, detail.ReleaseVersion
+	}
+	return component.Files, component.Version
+}
+
+// archiveURL 返回安装包的下载地址
+// 配置了镜像时，不在 dotnetcli 存储中的地址（如 download.visualstudio.microsoft.com）
+// 转换为 dotnetcli 的目录结构，以便从镜像下载
+func (p *DotNetSDKProvider) archiveURL(file ReleaseFile, componentVersion string) string {
+	if !p.mirrors.Archives.Configured() || file.Name == "" || componentVersion == "" {
+		return file.URL
+	}
+
+	for _, upstream := range dotnetCLIURLs {
+		if strings.HasPrefix(file.URL, upstream+"/") {
+			return file.URL
+		}
+	}
+
+	dir, ok := dotnetArchiveDirs[p.componentType]
+	if !ok {
+		return file.URL
 	}
+	return fmt.Sprintf("%s/%s/%s/%s", p.mirrors.Archives.Upstream(), dir, componentVersion, file.Name)
+}
+
+// newDotNetReleaseFile 将releases.json中的文件转换为下载文件，平台信息来自RID（如linux-x64、osx-arm64）
+func newDotNetReleaseFile(file DotNetComponentFile) ReleaseFile {
+	releaseFile := ReleaseFile{
+		Name:              file.Name,
+		URL:               file.URL,
+		Checksum:          file.Hash,
+		ChecksumAlgorithm: "sha512",
+	}
+	if file.HashAlgorithm != "" {
+		releaseFile.ChecksumAlgorithm = strings.ToLower(file.HashAlgorithm)
+	}
+
+	if parts := strings.Split(file.RID, "-"); len(parts) >= 2 {
+		switch parts[0] {
+		case "win":
+			releaseFile.OS = "windows"
+		case "osx":
+			releaseFile.OS = "darwin"
+		default:
+			releaseFile.OS = parts[0]
+		}
+		releaseFile.Arch = parts[len(parts)-1]
+	}
+	return releaseFile
+}
+
+// GetDownloadURL 实现SDKProvider接口，获取下载URL
+func (p *DotNetSDKProvider) GetDownloadURL(release Release, osName, arch string) (ReleaseFile, error) {
+	version := release.Version
+	files := release.Files
 
 	// 构建RID（Runtime Identifier）
 	var rid string
@@ -513,7 +593,7 @@ func (p *DotNetSDKProvider) GetDownloadURL(version, osName, arch string) string
 	utils.Log.Search(fmt.Sprintf("查找适用于 %s 的 %s %s 下载", rid, p.componentType, version))
 
 	// 首先尝试查找精确匹配的文件
-	var bestMatch string
+	var bestMatch ReleaseFile
 	var bestMatchScore int = -1
 
 	for _, file := range files {
@@ -584,18 +664,22 @@ func (p *DotNetSDKProvider) GetDownloadURL(version, osName, arch string) string
 		// 更新最佳匹配
 		if score > bestMatchScore {
 			bestMatchScore = score
-			bestMatch = file.URL
+			bestMatch = file
 			utils.Log.Info(fmt.Sprintf("找到更好的匹配: %s (分数: %d)", file.Name, score))
 		}
 	}
 
-	if bestMatch != "" {
-		utils.Log.Download(fmt.Sprintf("已找到下载链接: %s", bestMatch))
-		return bestMatch
+	if bestMatchScore >= 0 {
+		utils.Log.Download(fmt.Sprintf("已找到下载链接: %s", bestMatch.URL))
+		return bestMatch, nil
 	}
 
-	utils.Log.Warning(fmt.Sprintf("未找到适用于 %s-%s 的 %s %s 下载", osName, arch, p.componentType, version))
-	return ""
+	return ReleaseFile{}, fmt.Errorf("未找到适用于 %s-%s 的 %s %s 下载", osName, arch, p.componentType, version)
+}
+
+// GetMirrors 实现SDKProvider接口
+func (p *DotNetSDKProvider) GetMirrors() *Mirrors {
+	return &p.mirrors
 }
 
 // GetExtractDir 实现SDKProvider接口，获取解压后的目录名
@@ -605,6 +689,11 @@ func (p *DotNetSDKProvider) GetExtractDir(version, downloadedFile string) string
 	return ""
 }
 
+// VersionPath 返回版本目录相对安装目录的路径，PostInstall 会将文件移动到 <组件>/<版本>
+func (p *DotNetSDKProvider) VersionPath(version string) string {
+	return filepath.Join(p.componentType, version)
+}
+
 // GetBinDir 实现SDKProvider接口，获取bin目录
 func (p *DotNetSDKProvider) GetBinDir(baseDir string) string {
 	return baseDir
@@ -870,26 +959,52 @@ func (p *DotNetSDKProvider) PostInstall(version, installDir string) error {
 	return nil
 }
 
-// GetArchiveType 实现SDKProvider接口，获取归档类型
-func (p *DotNetSDKProvider) GetArchiveType() string {
-	return "auto"
+// DotNetGlobalJSON 表示global.json中的SDK配置
+type DotNetGlobalJSON struct {
+	SDK struct {
+		Version     string `json:"version"`
+		RollForward string `json:"rollForward"`
+	} `json:"sdk"`
 }
 
-// GetArchiveTypeForFile 实现SDKProvider接口，根据具体文件确定归档类型
-func (p *DotNetSDKProvider) GetArchiveTypeForFile(filePath string) string {
-	utils.Log.Info(fmt.Sprintf("检测文件类型: %s", filePath))
+// GetVersionFiles 获取.NET生态的版本文件名，只有SDK组件使用global.json
+func (p *DotNetSDKProvider) GetVersionFiles() []string {
+	if p.componentType != "sdk" {
+		return nil
+	}
+	return []string{"global.json"}
+}
+
+// ParseVersionFile 解析global.json中的sdk.version和rollForward
+// svm按发布版本（通道）管理.NET，因此根据rollForward策略将SDK版本转换为通道范围
+func (p *DotNetSDKProvider) ParseVersionFile(filePath string) (string, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return "", err
+	}
+
+	var globalJSON DotNetGlobalJSON
+	if err := json.Unmarshal(data, &globalJSON); err != nil {
+		return "", fmt.Errorf("解析global.json失败: %w", err)
+	}
+
+	version := globalJSON.SDK.Version
+	if version == "" {
+		return "", nil
+	}
+
+	parsed, err := utils.ParseVersion(version)
+	if err != nil || len(parsed.Segments()) < 2 {
+		return "", fmt.Errorf("无效的SDK版本: %s", version)
+	}
 
-	if strings.HasSuffix(filePath, ".zip") {
-		utils.Log.Extract("检测到zip文件")
-		return "zip"
-	} else if strings.HasSuffix(filePath, ".tar.gz") {
-		utils.Log.Extract("检测到tar.gz文件")
-		return "tar.gz"
-	} else if strings.HasSuffix(filePath, ".pkg") {
-		utils.Log.Extract("检测到pkg文件")
-		return "pkg"
+	// 未指定rollForward时默认为latestPatch
+	switch strings.ToLower(globalJSON.SDK.RollForward) {
+	case "latestmajor":
+		return "latest", nil
+	case "minor", "latestminor", "major":
+		return fmt.Sprintf("%d", parsed.Major()), nil
+	default:
+		return fmt.Sprintf("%d.%d", parsed.Major(), parsed.Minor()), nil
 	}
-	// 不再处理.exe文件
-	utils.Log.Warning(fmt.Sprintf("未知文件类型: %s", filePath))
-	return "unknown"
 }
@@ -3,8 +3,6 @@ package sdk
 import (
 	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
@@ -14,15 +12,34 @@ import (
 
 // GoVersion 表示Go版本信息
 type GoVersion struct {
-	Version string `json:"version"`
-	Stable  bool   `json:"stable"`
+	Version string   `json:"version"`
+	Stable  bool     `json:"stable"`
+	Files   []GoFile `json:"files"`
+}
+
+// GoFile 表示Go版本中的下载文件
+type GoFile struct {
+	Filename string `json:"filename"`
+	OS       string `json:"os"`
+	Arch     string `json:"arch"`
+	SHA256   string `json:"sha256"`
+	Size     int64  `json:"size"`
+	Kind     string `json:"kind"` // archive、installer、source
 }
 
 // GoSDKProvider 实现了SDKProvider接口
 type GoSDKProvider struct {
-	config *config.Config
+	config   *config.Config
+	versions []GoVersion // 已获取的版本列表
+	mirrors  Mirrors
 }
 
+// Go官方的版本列表和下载地址
+const (
+	goDLURL       = "https://go.dev/dl"
+	goDownloadURL = "https://dl.google.com/go"
+)
+
 // goSDK 是Go SDK的具体实现
 type goSDK struct {
 	BaseSDK
@@ -32,6 +49,10 @@ type goSDK struct {
 func NewGoSDK() SDK {
 	provider := &GoSDKProvider{
 		config: nil, // 这里为空，会由BaseSDK初始化时设置
+		mirrors: Mirrors{
+			Metadata: NewMirror(goDLURL),
+			Archives: NewMirror(goDownloadURL),
+		},
 	}
 
 	return &goSDK{
@@ -39,112 +60,92 @@ func NewGoSDK() SDK {
 	}
 }
 
-// GetVersionList 实现SDKProvider接口，获取所有可用的Go版本
-func (p *GoSDKProvider) GetVersionList() ([]string, error) {
-	// 从Go官网API获取版本列表
-	resp, err := http.Get("https://go.dev/dl/?mode=json&include=all")
+// GetReleases 实现SDKProvider接口，获取每个次版本的最新Go版本
+func (p *GoSDKProvider) GetReleases() ([]Release, error) {
+	releases, err := p.GetAllReleases()
 	if err != nil {
-		return nil, fmt.Errorf("获取版本列表失败: %w", err)
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("读取响应失败: %w", err)
-	}
+	// 按次版本分组（例如：1.20.3 -> 1.20），只保留最新版本
+	return latestReleasePerGroup(releases, minorGroup), nil
+}
 
-	var versions []struct {
-		Version string `json:"version"`
-		Stable  bool   `json:"stable"`
-	}
-	if err := json.Unmarshal(body, &versions); err != nil {
-		return nil, fmt.Errorf("解析版本列表失败: %w", err)
+// GetAllReleases 实现SDKProvider接口，获取所有可用的Go版本（不过滤）
+func (p *GoSDKProvider) GetAllReleases() ([]Release, error) {
+	versions, err := p.fetchVersions()
+	if err != nil {
+		return nil, err
 	}
 
-	// 提取版本号，按次版本分组
-	minorVersions := make(map[string]string)
+	// 提取所有稳定版本
+	var releases []Release
 	for _, v := range versions {
-		if v.Stable {
-			version := v.Version
-			if strings.HasPrefix(version, "go") {
-				version = version[2:] // 移除"go"前缀
-			}
+		if !v.Stable {
+			continue
+		}
 
-			// 提取次版本号（例如：1.20.3 -> 1.20）
-			parts := strings.Split(version, ".")
-			if len(parts) >= 2 {
-				minorVersion := parts[0] + "." + parts[1]
-
-				// 如果该次版本尚未记录或当前版本更新，则更新
-				if currentVersion, exists := minorVersions[minorVersion]; !exists {
-					minorVersions[minorVersion] = version
-				} else {
-					// 比较版本号
-					if version > currentVersion {
-						minorVersions[minorVersion] = version
-					}
-				}
-			} else {
-				// 对于格式不符合预期的版本，直接添加
-				minorVersions[version] = version
+		release := Release{Version: strings.TrimPrefix(v.Version, "go")} // 移除"go"前缀
+		for _, file := range v.Files {
+			if file.Kind != "archive" {
+				continue
 			}
+			release.Files = append(release.Files, ReleaseFile{
+				Name:              file.Filename,
+				URL:               goDownloadURL + "/" + file.Filename,
+				OS:                file.OS,
+				Arch:              goArchName(file.Arch),
+				Size:              file.Size,
+				Checksum:          file.SHA256,
+				ChecksumAlgorithm: "sha256",
+			})
 		}
-	}
 
-	// 将次版本映射转换为版本列表
-	var versionList []string
-	for _, version := range minorVersions {
-		versionList = append(versionList, version)
+		releases = append(releases, release)
 	}
 
 	// 按版本号排序（从新到旧）
-	utils.SortVersionsDesc(versionList)
+	SortReleasesDesc(releases)
 
-	return versionList, nil
+	return releases, nil
 }
 
-// GetAllVersionList 实现SDKProvider接口，获取所有可用的Go版本（不过滤）
-func (p *GoSDKProvider) GetAllVersionList() ([]string, error) {
-	// 从Go官网API获取版本列表
-	resp, err := http.Get("https://go.dev/dl/?mode=json&include=all")
-	if err != nil {
-		return nil, fmt.Errorf("获取版本列表失败: %w", err)
+// fetchVersions 获取Go官网的版本列表，同一进程内只请求一次
+func (p *GoSDKProvider) fetchVersions() ([]GoVersion, error) {
+	if p.versions != nil {
+		return p.versions, nil
 	}
-	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
+	// 从Go官网API（或镜像）获取版本列表
+	body, err := p.mirrors.Metadata.Fetch(goDLURL + "/?mode=json&include=all")
 	if err != nil {
-		return nil, fmt.Errorf("读取响应失败: %w", err)
+		return nil, fmt.Errorf("获取版本列表失败: %w", err)
 	}
 
-	var versions []struct {
-		Version string `json:"version"`
-		Stable  bool   `json:"stable"`
-	}
+	var versions []GoVersion
 	if err := json.Unmarshal(body, &versions); err != nil {
 		return nil, fmt.Errorf("解析版本列表失败: %w", err)
 	}
 
-	// 提取所有稳定版本号
-	var versionList []string
-	for _, v := range versions {
-		if v.Stable {
-			version := v.Version
-			if strings.HasPrefix(version, "go") {
-				version = version[2:] // 移除"go"前缀
-			}
-			versionList = append(versionList, version)
-		}
-	}
-
-	// 按版本号排序（从新到旧）
-	utils.SortVersionsDesc(versionList)
+	p.versions = versions
+	return versions, nil
+}
 
-	return versionList, nil
+// goArchName 将Go的架构名称转换为svm使用的名称
+func goArchName(arch string) string {
+	switch arch {
+	case "amd64":
+		return "x64"
+	case "386":
+		return "x86"
+	}
+	return arch
 }
 
 // GetDownloadURL 构建Go下载URL
-func (p *GoSDKProvider) GetDownloadURL(version, osName, arch string) string {
+func (p *GoSDKProvider) GetDownloadURL(release Release, osName, arch string) (ReleaseFile, error) {
+	version := release.Version
+
 	// 适配操作系统名称
 	goOs := osName
 	if osName == "darwin" {
@@ -171,8 +172,22 @@ func (p *GoSDKProvider) GetDownloadURL(version, osName, arch string) string {
 		ext = "zip"
 	}
 
+	// 优先使用版本元数据中的文件（带有校验值）
+	fileName := fmt.Sprintf("go%s.%s-%s.%s", version, goOs, goArch, ext)
+	if file, ok := release.FindFile(fileName); ok {
+		return file, nil
+	}
+
 	// 构建下载URL
-	return fmt.Sprintf("https://dl.google.com/go/go%s.%s-%s.%s", version, goOs, goArch, ext)
+	return ReleaseFile{
+		Name: fileName,
+		URL:  goDownloadURL + "/" + fileName,
+	}, nil
+}
+
+// GetMirrors 实现SDKProvider接口
+func (p *GoSDKProvider) GetMirrors() *Mirrors {
+	return &p.mirrors
 }
 
 // GetExtractDir 获取解压后的目录名
@@ -261,18 +276,72 @@ func (p *GoSDKProvider) PostInstall(version, installDir string) error {
 	return nil
 }
 
-// GetArchiveType 获取归档类型
-func (p *GoSDKProvider) GetArchiveType() string {
-	return "zip"
+// IsInstallation 判断目录是否是Go安装目录
+func (p *GoSDKProvider) IsInstallation(dir string) bool {
+	return findExecutable(dir, "go") != ""
+}
+
+// DiscoverInstallations 在常见位置查找已有的Go安装，版本号从 VERSION 文件读取
+func (p *GoSDKProvider) DiscoverInstallations() []Installation {
+	dirs := existingDirs(
+		"/usr/local/go", "/usr/lib/go", "/usr/lib/go-*", "/usr/lib/golang",
+		"/opt/homebrew/opt/go/libexec", "/usr/local/opt/go/libexec",
+		"~/sdk/go*", `C:\Program Files\Go`,
+	)
+
+	var installations []Installation
+	for _, dir := range dirs {
+		if !p.IsInstallation(dir) {
+			continue
+		}
+
+		version := filepath.Base(dir)
+		if data, err := os.ReadFile(filepath.Join(dir, "VERSION")); err == nil {
+			firstLine, _, _ := strings.Cut(string(data), "\n")
+			version = strings.TrimPrefix(strings.TrimSpace(firstLine), "go")
+		}
+		installations = append(installations, Installation{Name: "system-" + version, Dir: dir})
+	}
+	return installations
 }
 
-// GetArchiveTypeForFile 根据文件名确定正确的归档类型
-func (p *GoSDKProvider) GetArchiveTypeForFile(filePath string) string {
-	fileName := filepath.Base(filePath)
-	if strings.HasSuffix(fileName, ".zip") {
-		return "zip"
-	} else if strings.HasSuffix(fileName, ".tar.gz") || strings.HasSuffix(fileName, ".tgz") {
-		return "tar.gz"
+// GetVersionFiles 获取Go生态的版本文件名
+func (p *GoSDKProvider) GetVersionFiles() []string {
+	return []string{"go.work", "go.mod"}
+}
+
+// ParseVersionFile 解析go.work或go.mod中的toolchain和go指令
+// toolchain 指令优先于 go 指令
+func (p *GoSDKProvider) ParseVersionFile(filePath string) (string, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return "", err
+	}
+
+	var goVersion, toolchain string
+	for _, line := range strings.Split(string(data), "\n") {
+		if idx := strings.Index(line, "//"); idx >= 0 {
+			line = line[:idx]
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+
+		switch fields[0] {
+		case "go":
+			goVersion = fields[1]
+		case "toolchain":
+			// toolchain default 表示使用go指令中的版本
+			if fields[1] != "default" {
+				toolchain = strings.TrimPrefix(fields[1], "go")
+			}
+		}
+	}
+
+	if toolchain != "" {
+		return toolchain, nil
 	}
-	return "zip" // 默认为zip
+	return goVersion, nil
 }
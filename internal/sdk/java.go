@@ -4,12 +4,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"net/http"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"svm/internal/config"
 	"svm/internal/utils"
+	"unicode"
 )
 
 // JavaVersion 表示Java版本信息
@@ -21,13 +22,24 @@ type JavaVersion struct {
 
 // JavaSDKProvider 实现了SDKProvider接口
 type JavaSDKProvider struct {
-	config *config.Config
+	config  *config.Config
+	mirrors Mirrors
 }
 
+// Adoptium 的API地址，API返回的安装包位于 GitHub 上的 adoptium 组织中
+const (
+	adoptiumAPIURL      = "https://api.adoptium.net"
+	adoptiumDownloadURL = "https://github.com/adoptium"
+)
+
 // NewJavaSDK 创建一个新的Java SDK
 func NewJavaSDK() SDK {
 	provider := &JavaSDKProvider{
 		config: nil, // 这里为空，会由BaseSDK初始化时设置
+		mirrors: Mirrors{
+			Metadata: NewMirror(adoptiumAPIURL),
+			Archives: NewMirror(adoptiumDownloadURL),
+		},
 	}
 
 	return &javaSDK{
@@ -35,53 +47,88 @@ func NewJavaSDK() SDK {
 	}
 }
 
+// javaFeatureVersion 将Java版本请求规范化为特性版本，例如 17.0.9+9、temurin-17.0.9+9、17.0.9-tem -> 17，1.8.0_392 -> 8
+// 范围约束和别名（^17、lts/*）保持不变
+func javaFeatureVersion(version string) string {
+	version = strings.TrimSpace(version)
+	if strings.ContainsAny(version, " \t<>=^~*|,/") {
+		return version
+	}
+
+	// 去掉发行商前缀（asdf 的 temurin-、jenv 的 temurin64-）和后缀（SDKMAN! 的 -tem）
+	for _, part := range strings.Split(version, "-") {
+		if part == "" || !unicode.IsDigit(rune(part[0])) {
+			continue
+		}
+
+		parsed, err := utils.ParseVersion(part)
+		if err != nil {
+			return version
+		}
+
+		// 1.8 形式的旧版本号，主版本号为8
+		feature := parsed.Major()
+		if feature == 1 {
+			feature = parsed.Minor()
+		}
+		return strconv.Itoa(feature)
+	}
+	return version
+}
+
 // javaSDK 是Java SDK的具体实现
 type javaSDK struct {
 	BaseSDK
 }
 
-// GetVersionList 实现SDKProvider接口，获取所有可用的Java版本
-func (p *JavaSDKProvider) GetVersionList() ([]string, error) {
-	// 从AdoptOpenJDK API获取版本列表
-	url := "https://api.adoptium.net/v3/info/available_releases"
-	resp, err := http.Get(url)
+// GetReleases 实现SDKProvider接口，获取所有可用的Java特性版本
+func (p *JavaSDKProvider) GetReleases() ([]Release, error) {
+	// 从AdoptOpenJDK API（或镜像）获取版本列表
+	body, err := p.mirrors.Metadata.Fetch(adoptiumAPIURL + "/v3/info/available_releases")
 	if err != nil {
 		return nil, fmt.Errorf("获取版本列表失败: %w", err)
 	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("读取响应失败: %w", err)
-	}
 
 	var data struct {
-		AvailableReleases []int `json:"available_releases"`
+		AvailableReleases    []int `json:"available_releases"`
+		AvailableLTSReleases []int `json:"available_lts_releases"`
 	}
 	if err := json.Unmarshal(body, &data); err != nil {
 		return nil, fmt.Errorf("解析版本列表失败: %w", err)
 	}
 
-	var versions []string
+	lts := make(map[int]bool)
+	for _, v := range data.AvailableLTSReleases {
+		lts[v] = true
+	}
+
+	var releases []Release
 	for _, v := range data.AvailableReleases {
-		versions = append(versions, fmt.Sprintf("%d", v))
+		release := Release{Version: fmt.Sprintf("%d", v)}
+		if lts[v] {
+			release.LTS = "LTS"
+		}
+		releases = append(releases, release)
 	}
 
 	// 按版本号排序（从新到旧）
-	utils.SortVersionsDesc(versions)
+	SortReleasesDesc(releases)
 
-	return versions, nil
+	return releases, nil
 }
 
-// GetAllVersionList 实现SDKProvider接口，获取所有可用的Java版本（不过滤）
-func (p *JavaSDKProvider) GetAllVersionList() ([]string, error) {
-	// 对于Java，GetVersionList已经返回所有版本，不需要额外过滤
-	// 这里直接调用GetVersionList
-	return p.GetVersionList()
+// GetAllReleases 实现SDKProvider接口，获取所有可用的Java版本（不过滤）
+func (p *JavaSDKProvider) GetAllReleases() ([]Release, error) {
+	// 对于Java，GetReleases已经返回所有版本，不需要额外过滤
+	// 这里直接调用GetReleases
+	return p.GetReleases()
 }
 
 // GetDownloadURL 构建Java下载URL
-func (p *JavaSDKProvider) GetDownloadURL(version, osName, arch string) string {
+// Adoptium 按特性版本提供最新构建，下载文件在安装时按平台查询
+func (p *JavaSDKProvider) GetDownloadURL(release Release, osName, arch string) (ReleaseFile, error) {
+	version := release.Version
+
 	// 适配操作系统名称
 	adoptOs := osName
 	if osName == "windows" {
@@ -104,39 +151,59 @@ func (p *JavaSDKProvider) GetDownloadURL(version, osName, arch string) string {
 
 	// 构建API URL
 	apiUrl := fmt.Sprintf(
-		"https://api.adoptium.net/v3/assets/latest/%s/hotspot?architecture=%s&os=%s&image_type=jdk&vendor=eclipse",
-		version, adoptArch, adoptOs,
+		"%s/v3/assets/latest/%s/hotspot?architecture=%s&os=%s&image_type=jdk&vendor=eclipse",
+		adoptiumAPIURL, version, adoptArch, adoptOs,
 	)
 
 	// 获取下载链接
-	resp, err := http.Get(apiUrl)
+	body, err := p.mirrors.Metadata.Fetch(apiUrl)
 	if err != nil {
-		utils.Log.Warning(fmt.Sprintf("获取下载链接失败: %v", err))
-		return ""
+		return ReleaseFile{}, fmt.Errorf("获取下载链接失败: %w", err)
 	}
-	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		utils.Log.Warning(fmt.Sprintf("读取下载链接失败: %v", err))
-		return ""
+	var assets []struct {
+		Binary struct {
+			Package struct {
+				Name     string `json:"name"`
+				Link     string `json:"link"`
+				Checksum string `json:"checksum"`
+				Size     int64  `json:"size"`
+			} `json:"package"`
+		} `json:"binary"`
 	}
 
-	var releases []struct {
-		BinaryLink string `json:"binary_link"`
+	if err := json.Unmarshal(body, &assets); err != nil {
+		return ReleaseFile{}, fmt.Errorf("解析下载链接失败: %w", err)
 	}
 
-	if err := json.Unmarshal(body, &releases); err != nil {
-		utils.Log.Warning(fmt.Sprintf("解析下载链接失败: %v", err))
-		return ""
+	if len(assets) == 0 || assets[0].Binary.Package.Link == "" {
+		return ReleaseFile{}, fmt.Errorf("未找到适合当前系统的Java版本")
 	}
 
-	if len(releases) == 0 {
-		utils.Log.Warning("警告：未找到适合当前系统的Java版本")
-		return ""
+	pkg := assets[0].Binary.Package
+	file := ReleaseFile{
+		Name: pkg.Name,
+		URL:  pkg.Link,
+		OS:   osName,
+		Arch: arch,
+		Size: pkg.Size,
 	}
+	if pkg.Checksum != "" {
+		file.Checksum = pkg.Checksum
+		file.ChecksumAlgorithm = "sha256"
+	}
+	return file, nil
+}
 
-	return releases[0].BinaryLink
+// NormalizeReleaseRequest 实现ReleaseRequestNormalizer接口
+// Adoptium 的版本列表只有特性版本，完整版本号（如 17.0.9+9）按特性版本查找最新构建
+func (p *JavaSDKProvider) NormalizeReleaseRequest(version string) string {
+	return javaFeatureVersion(version)
+}
+
+// GetMirrors 实现SDKProvider接口
+func (p *JavaSDKProvider) GetMirrors() *Mirrors {
+	return &p.mirrors
 }
 
 // GetExtractDir 获取解压后的目录名
@@ -257,22 +324,33 @@ func (p *JavaSDKProvider) PostInstall(version, installDir string) error {
 	return nil
 }
 
-// GetArchiveType 获取归档类型
-func (p *JavaSDKProvider) GetArchiveType() string {
-	return "zip"
+// IsInstallation 判断目录是否是JDK或JRE的安装目录
+func (p *JavaSDKProvider) IsInstallation(dir string) bool {
+	return findExecutable(dir, "java") != ""
 }
 
-// GetArchiveTypeForFile 根据文件名确定正确的归档类型
-func (p *JavaSDKProvider) GetArchiveTypeForFile(filePath string) string {
-	fileName := filepath.Base(filePath)
-	if strings.HasSuffix(fileName, ".zip") {
-		return "zip"
-	} else if strings.HasSuffix(fileName, ".tar.gz") || strings.HasSuffix(fileName, ".tgz") {
-		return "tar.gz"
-	} else if strings.HasSuffix(fileName, ".exe") || strings.HasSuffix(fileName, ".msi") {
-		return "none"
-	}
-	return "zip" // 默认为zip
+// DiscoverInstallations 在常见位置查找已有的JDK，以目录名作为名称
+func (p *JavaSDKProvider) DiscoverInstallations() []Installation {
+	dirs := existingDirs(
+		"/usr/lib/jvm/*", "/usr/java/*", "/Library/Java/JavaVirtualMachines/*/Contents/Home",
+		"~/.sdkman/candidates/java/*",
+		`C:\Program Files\Java\*`, `C:\Program Files\Eclipse Adoptium\*`, `C:\Program Files\Microsoft\jdk-*`,
+	)
+
+	var installations []Installation
+	for _, dir := range dirs {
+		if !p.IsInstallation(dir) {
+			continue
+		}
+
+		name := filepath.Base(dir)
+		if name == "Home" {
+			// macOS 的 <名称>.jdk/Contents/Home
+			name = strings.TrimSuffix(filepath.Base(filepath.Dir(filepath.Dir(dir))), ".jdk")
+		}
+		installations = append(installations, Installation{Name: name, Dir: dir})
+	}
+	return installations
 }
 
 // copyFile 辅助函数，用于复制文件
@@ -302,3 +380,36 @@ func copyFile(src, dst string) error {
 
 	return os.Chmod(dst, srcInfo.Mode())
 }
+
+// GetVersionFiles 获取Java生态的版本文件名
+func (p *JavaSDKProvider) GetVersionFiles() []string {
+	return []string{".java-version", ".sdkmanrc"}
+}
+
+// ParseVersionFile 解析jenv的.java-version或SDKMAN!的.sdkmanrc文件
+// Adoptium 按特性版本（主版本号）提供最新构建，因此只返回主版本号
+func (p *JavaSDKProvider) ParseVersionFile(filePath string) (string, error) {
+	var version string
+	var err error
+
+	if filepath.Base(filePath) == ".sdkmanrc" {
+		// .sdkmanrc 格式为 java=17.0.9-tem
+		version, err = readVersionFileProperty(filePath, "java")
+	} else {
+		// .java-version 可能带有发行商前缀，例如 temurin64-17.0.9
+		version, err = readVersionFileLine(filePath)
+	}
+	if err != nil || version == "" {
+		return "", err
+	}
+
+	feature := javaFeatureVersion(version)
+	if _, err := strconv.Atoi(feature); err != nil {
+		return "", fmt.Errorf("无效的Java版本: %s", version)
+	}
+	if feature != version {
+		utils.Log.Info(fmt.Sprintf("Java版本文件指定了 %s，将使用特性版本 %s 的最新构建", version, feature))
+	}
+
+	return feature, nil
+}
@@ -0,0 +1,104 @@
+package sdk
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestJavaFeatureVersion(t *testing.T) {
+	tests := []struct {
+		version string
+		want    string
+	}{
+		{"17", "17"},
+		{"17.0.9", "17"},
+		{"17.0.9+9", "17"},
+		{"21.0.1", "21"},
+		{"21+35", "21"},
+		{"1.8", "8"},
+		{"1.8.0_392", "8"},
+		{"temurin-17.0.9+9", "17"},
+		{"temurin64-17.0.9", "17"},
+		{"17.0.9-tem", "17"},
+		{" 11.0.21 ", "11"},
+		{"^17", "^17"},
+		{">=17 <21", ">=17 <21"},
+		{"17 - 21", "17 - 21"},
+		{"lts/*", "lts/*"},
+		{"temurin", "temurin"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			if got := javaFeatureVersion(tt.version); got != tt.want {
+				t.Errorf("javaFeatureVersion(%q) = %q, 期望 %q", tt.version, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJavaFindMatchingReleases(t *testing.T) {
+	releases := []Release{{Version: "21", LTS: "LTS"}, {Version: "17", LTS: "LTS"}, {Version: "11", LTS: "LTS"}, {Version: "8", LTS: "LTS"}}
+
+	tests := []struct {
+		request string
+		want    []string
+	}{
+		{"17", []string{"17"}},
+		{"17.0.9+9", []string{"17"}},
+		{"temurin-21.0.1", []string{"21"}},
+		{"1.8.0_392", []string{"8"}},
+		{">=17", []string{"21", "17"}},
+	}
+
+	b := &BaseSDK{Name: "java", Provider: &JavaSDKProvider{}}
+	for _, tt := range tests {
+		t.Run(tt.request, func(t *testing.T) {
+			got, err := b.FindMatchingReleases(tt.request, releases, DefaultVersionPrefixHandlers())
+			if err != nil {
+				t.Fatalf("FindMatchingReleases(%q) 失败: %v", tt.request, err)
+			}
+			if versions := ReleaseVersions(got); !slices.Equal(versions, tt.want) {
+				t.Errorf("FindMatchingReleases(%q) = %v，期望 %v", tt.request, versions, tt.want)
+			}
+		})
+	}
+}
+
+func TestJavaParseVersionFile(t *testing.T) {
+	tests := []struct {
+		file    string
+		content string
+		want    string
+		wantErr bool
+	}{
+		{file: ".java-version", content: "17.0.9\n", want: "17"},
+		{file: ".java-version", content: "temurin64-21.0.1\n", want: "21"},
+		{file: ".java-version", content: "1.8\n", want: "8"},
+		{file: ".sdkmanrc", content: "java=17.0.9-tem\n", want: "17"},
+		{file: ".java-version", content: "latest\n", wantErr: true},
+	}
+
+	provider := &JavaSDKProvider{}
+	for _, tt := range tests {
+		t.Run(tt.file+"/"+tt.content, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tt.file)
+			if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := provider.ParseVersionFile(path)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("期望返回错误，实际为 %q", got)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("ParseVersionFile() = %q, %v，期望 %q", got, err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,199 @@
+package sdk
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"svm/internal/config"
+	"svm/internal/utils"
+)
+
+// Installation 表示系统中已有的一个安装
+type Installation struct {
+	Name string // 建议登记的名称，例如 temurin-17-jdk、system-1.22.3
+	Dir  string // 安装目录
+}
+
+// InstallationDiscoverer 由支持登记外部目录的SDK实现
+type InstallationDiscoverer interface {
+	// IsInstallation 判断目录是否是有效的安装目录
+	IsInstallation(dir string) bool
+
+	// DiscoverInstallations 在系统的常见位置查找已有的安装
+	DiscoverInstallations() []Installation
+}
+
+// versionNumberPattern 匹配命令输出中的版本号
+var versionNumberPattern = regexp.MustCompile(`\d+\.\d+(\.\d+)?`)
+
+// Link 将已有的外部目录登记为指定名称的已安装版本，之后可以用 Use 切换，Remove 时只取消登记
+func (b *BaseSDK) Link(name, dir string) error {
+	discoverer, ok := b.Provider.(InstallationDiscoverer)
+	if !ok {
+		return fmt.Errorf("%s 不支持登记外部目录", b.Name)
+	}
+
+	if name == "" || name == "current" || strings.ContainsAny(name, `/\`) {
+		return fmt.Errorf("无效的名称: %q", name)
+	}
+
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return fmt.Errorf("获取绝对路径失败: %w", err)
+	}
+	if exists, _ := utils.CheckDirExists(absDir); !exists {
+		return fmt.Errorf("目录不存在: %s", dir)
+	}
+	if !discoverer.IsInstallation(absDir) {
+		return fmt.Errorf("%s 不是有效的%s安装目录", absDir, b.Name)
+	}
+
+	// 不能覆盖由svm安装的版本
+	info, exists := b.Config.GetVersionInfo(b.GetName(), name)
+	managedDir, _ := utils.CheckDirExists(filepath.Join(b.InstallDir, name))
+	if (exists && info.InstallDir != "" && !info.Linked) || managedDir {
+		return fmt.Errorf("版本 %s 已由svm安装，请使用其他名称", name)
+	}
+
+	if err := b.Config.SetVersionInfo(b.GetName(), name, config.SDKVersionInfo{InstallDir: absDir, Linked: true}); err != nil {
+		return fmt.Errorf("保存配置失败: %w", err)
+	}
+
+	utils.Log.Link(fmt.Sprintf("已登记 %s %s -> %s", b.Name, name, absDir))
+	return nil
+}
+
+// Discover 在系统的常见位置查找尚未登记的已有安装，名称与已有版本重复时添加序号
+func (b *BaseSDK) Discover() ([]Installation, error) {
+	discoverer, ok := b.Provider.(InstallationDiscoverer)
+	if !ok {
+		return nil, fmt.Errorf("%s 不支持登记外部目录", b.Name)
+	}
+
+	// 已登记或由svm安装的目录及已使用的名称
+	knownDirs := make(map[string]bool)
+	usedNames := make(map[string]bool)
+	for version, info := range b.Config.SDKs[b.GetName()].VersionCache {
+		usedNames[version] = true
+		if info.InstallDir != "" {
+			knownDirs[resolveDir(info.InstallDir)] = true
+		}
+	}
+	managedRoot := resolveDir(b.InstallDir)
+
+	var found []Installation
+	for _, installation := range discoverer.DiscoverInstallations() {
+		dir := resolveDir(installation.Dir)
+		if knownDirs[dir] || strings.HasPrefix(dir, managedRoot+string(filepath.Separator)) {
+			continue
+		}
+		knownDirs[dir] = true
+
+		name := installation.Name
+		for i := 2; usedNames[name]; i++ {
+			name = fmt.Sprintf("%s-%d", installation.Name, i)
+		}
+		usedNames[name] = true
+
+		found = append(found, Installation{Name: name, Dir: installation.Dir})
+	}
+	return found, nil
+}
+
+// normalizeVersion 规范化版本号，登记的外部目录保持原来的名称
+func (b *BaseSDK) normalizeVersion(version string) string {
+	if info, ok := b.Config.GetVersionInfo(b.GetName(), version); ok && info.Linked {
+		return version
+	}
+	return b.VersionHandlers.Add(version)
+}
+
+// linkedDir 返回登记的外部目录，版本不是外部目录时 linked 为 false
+func (b *BaseSDK) linkedDir(version string) (dir string, linked bool) {
+	info, ok := b.Config.GetVersionInfo(b.GetName(), version)
+	if !ok || !info.Linked {
+		return "", false
+	}
+	return info.InstallDir, true
+}
+
+// findVersionDir 查找已安装版本的目录：登记的外部目录，或安装目录下的 <版本>、sdk/<版本>
+func (b *BaseSDK) findVersionDir(version string) (string, bool) {
+	if dir, linked := b.linkedDir(version); linked {
+		exists, _ := utils.CheckDirExists(dir)
+		return dir, exists
+	}
+
+	for _, dir := range []string{filepath.Join(b.InstallDir, version), filepath.Join(b.InstallDir, "sdk", version)} {
+		if exists, _ := utils.CheckDirExists(dir); exists {
+			return dir, true
+		}
+	}
+	return "", false
+}
+
+// resolveDir 返回解析符号链接后的绝对路径，用于比较目录是否相同
+func resolveDir(dir string) string {
+	if resolved, err := filepath.EvalSymlinks(dir); err == nil {
+		dir = resolved
+	}
+	if abs, err := filepath.Abs(dir); err == nil {
+		dir = abs
+	}
+	return filepath.Clean(dir)
+}
+
+// existingDirs 返回匹配各模式的目录，模式中开头的 ~ 表示用户主目录，指向同一目录的符号链接只保留一个
+func existingDirs(patterns ...string) []string {
+	homeDir, _ := os.UserHomeDir()
+
+	seen := make(map[string]bool)
+	var dirs []string
+	for _, pattern := range patterns {
+		if rest, ok := strings.CutPrefix(pattern, "~"); ok {
+			if homeDir == "" {
+				continue
+			}
+			pattern = homeDir + rest
+		}
+
+		matches, _ := filepath.Glob(filepath.FromSlash(pattern))
+		for _, match := range matches {
+			if exists, _ := utils.CheckDirExists(match); !exists {
+				continue
+			}
+			if resolved := resolveDir(match); !seen[resolved] {
+				seen[resolved] = true
+				dirs = append(dirs, match)
+			}
+		}
+	}
+	return dirs
+}
+
+// findExecutable 在目录或其 bin 子目录中查找可执行文件，未找到时返回空字符串
+func findExecutable(dir string, names ...string) string {
+	for _, name := range names {
+		if runtime.GOOS == "windows" {
+			name += ".exe"
+		}
+		for _, path := range []string{filepath.Join(dir, "bin", name), filepath.Join(dir, name)} {
+			if info, err := os.Stat(path); err == nil && !info.IsDir() {
+				return path
+			}
+		}
+	}
+	return ""
+}
+
+// executableVersion 运行可执行文件并从输出中解析版本号，失败时返回空字符串
+func executableVersion(path string, args ...string) string {
+	output, err := utils.RunCommand(path, args...)
+	if err != nil {
+		return ""
+	}
+	return versionNumberPattern.FindString(output)
+}
@@ -0,0 +1,105 @@
+package sdk
+
+import (
+	"fmt"
+	"strings"
+	"svm/internal/config"
+	"svm/internal/utils"
+)
+
+// Mirror 表示一类地址（版本元数据或下载文件）的官方地址及配置的镜像地址
+// 镜像需要与官方地址有相同的目录结构，使用时将URL中的官方地址前缀替换为镜像地址
+type Mirror struct {
+	upstreams []string             // 官方地址，第一个为主地址，其余为目录结构相同的别名
+	bases     []string             // 按顺序尝试的镜像地址，为空时使用官方地址
+	cache     *utils.MetadataCache // 获取内容的缓存，为空时不缓存
+}
+
+// NewMirror 创建镜像配置，upstreams 为官方地址及其别名
+func NewMirror(upstreams ...string) Mirror {
+	return Mirror{upstreams: upstreams}
+}
+
+// Upstream 返回官方主地址
+func (m *Mirror) Upstream() string {
+	if len(m.upstreams) == 0 {
+		return ""
+	}
+	return m.upstreams[0]
+}
+
+// SetBases 设置按顺序尝试的镜像地址
+func (m *Mirror) SetBases(bases []string) {
+	m.bases = nil
+	for _, base := range bases {
+		if base = strings.TrimRight(strings.TrimSpace(base), "/"); base != "" {
+			m.bases = append(m.bases, base)
+		}
+	}
+}
+
+// Configured 判断是否配置了镜像
+func (m *Mirror) Configured() bool {
+	return len(m.bases) > 0
+}
+
+// URLs 返回按顺序尝试的地址，不在官方地址下的URL（例如第三方地址）保持不变
+func (m *Mirror) URLs(rawURL string) []string {
+	if !m.Configured() {
+		return []string{rawURL}
+	}
+
+	for _, upstream := range m.upstreams {
+		rest, ok := strings.CutPrefix(rawURL, upstream)
+		if !ok || (rest != "" && !strings.HasPrefix(rest, "/") && !strings.HasPrefix(rest, "?")) {
+			continue
+		}
+
+		urls := make([]string, 0, len(m.bases))
+		for _, base := range m.bases {
+			urls = append(urls, base+rest)
+		}
+		return urls
+	}
+	return []string{rawURL}
+}
+
+// SetCache 设置获取内容使用的缓存
+func (m *Mirror) SetCache(cache *utils.MetadataCache) {
+	m.cache = cache
+}
+
+// Fetch 依次从各个镜像获取内容，一个镜像失败时尝试下一个
+// 设置了缓存时以官方地址为缓存键，切换镜像后仍可使用已有的缓存
+func (m *Mirror) Fetch(rawURL string) ([]byte, error) {
+	urls := m.URLs(rawURL)
+	if m.cache != nil {
+		return m.cache.Fetch(rawURL, urls)
+	}
+
+	var lastErr error
+	for i, url := range urls {
+		data, err := utils.FetchBytes(url)
+		if err == nil {
+			return data, nil
+		}
+
+		lastErr = err
+		if i < len(urls)-1 {
+			utils.Log.Warning(fmt.Sprintf("从 %s 获取失败: %v，尝试下一个镜像", url, err))
+		}
+	}
+	return nil, lastErr
+}
+
+// Mirrors 保存SDK的版本元数据和下载文件的镜像配置
+type Mirrors struct {
+	Metadata Mirror // 版本列表等元数据
+	Archives Mirror // 安装包、校验文件和签名
+}
+
+// Configure 应用配置文件中的镜像地址
+func (m *Mirrors) Configure(mirrors config.MirrorConfig) {
+	m.Metadata.SetBases(mirrors.Metadata)
+	m.Archives.SetBases(mirrors.Archives)
+}
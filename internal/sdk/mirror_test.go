@@ -0,0 +1,75 @@
+package sdk
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestMirrorURLs(t *testing.T) {
+	tests := []struct {
+		name      string
+		upstreams []string
+		bases     []string
+		url       string
+		want      []string
+	}{
+		{
+			name:      "未配置镜像",
+			upstreams: []string{"https://nodejs.org/dist"},
+			url:       "https://nodejs.org/dist/index.json",
+			want:      []string{"https://nodejs.org/dist/index.json"},
+		},
+		{
+			name:      "替换官方地址",
+			upstreams: []string{"https://nodejs.org/dist"},
+			bases:     []string{"https://npmmirror.com/mirrors/node/"},
+			url:       "https://nodejs.org/dist/v20.11.1/SHASUMS256.txt",
+			want:      []string{"https://npmmirror.com/mirrors/node/v20.11.1/SHASUMS256.txt"},
+		},
+		{
+			name:      "按顺序尝试多个镜像",
+			upstreams: []string{"https://go.dev/dl"},
+			bases:     []string{"https://golang.google.cn/dl", " ", "https://mirrors.example.com/go"},
+			url:       "https://go.dev/dl/?mode=json&include=all",
+			want:      []string{"https://golang.google.cn/dl/?mode=json&include=all", "https://mirrors.example.com/go/?mode=json&include=all"},
+		},
+		{
+			name:      "官方地址的别名",
+			upstreams: []string{"https://dotnetcli.azureedge.net/dotnet", "https://builds.dotnet.microsoft.com/dotnet"},
+			bases:     []string{"https://mirror.example.com/dotnet"},
+			url:       "https://builds.dotnet.microsoft.com/dotnet/Sdk/8.0.100/dotnet-sdk.tar.gz",
+			want:      []string{"https://mirror.example.com/dotnet/Sdk/8.0.100/dotnet-sdk.tar.gz"},
+		},
+		{
+			name:      "官方地址本身",
+			upstreams: []string{"https://nodejs.org/dist"},
+			bases:     []string{"https://npmmirror.com/mirrors/node"},
+			url:       "https://nodejs.org/dist",
+			want:      []string{"https://npmmirror.com/mirrors/node"},
+		},
+		{
+			name:      "前缀相同但不是官方目录",
+			upstreams: []string{"https://nodejs.org/dist"},
+			bases:     []string{"https://npmmirror.com/mirrors/node"},
+			url:       "https://nodejs.org/distribution/index.json",
+			want:      []string{"https://nodejs.org/distribution/index.json"},
+		},
+		{
+			name:      "第三方地址保持不变",
+			upstreams: []string{"https://www.python.org/ftp/python"},
+			bases:     []string{"https://mirrors.example.com/python"},
+			url:       "https://www.python.org/api/v2/downloads/release/?name=Python%203.12.4",
+			want:      []string{"https://www.python.org/api/v2/downloads/release/?name=Python%203.12.4"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mirror := NewMirror(tt.upstreams...)
+			mirror.SetBases(tt.bases)
+			if got := mirror.URLs(tt.url); !slices.Equal(got, tt.want) {
+				t.Errorf("URLs(%q) = %v，期望 %v", tt.url, got, tt.want)
+			}
+		})
+	}
+}
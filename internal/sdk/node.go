@@ -1,10 +1,9 @@
 package sdk
 
 import (
+	_ "embed"
 	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
 	"path/filepath"
 	"runtime"
 	"strings"
@@ -14,20 +13,31 @@ import (
 
 // NodeVersion 表示Node.js版本信息
 type NodeVersion struct {
-	Version string   `json:"version"`
-	Date    string   `json:"date"`
-	Files   []string `json:"files"`
+	Version  string      `json:"version"`
+	Date     string      `json:"date"`
+	Files    []string    `json:"files"`
+	LTS      interface{} `json:"lts"` // 非LTS版本为false，LTS版本为代号（如Hydrogen）
+	Security bool        `json:"security"`
 }
 
 // NodeSDKProvider 实现了SDKProvider接口
 type NodeSDKProvider struct {
-	config *config.Config
+	config  *config.Config
+	index   []NodeVersion // 已获取的版本索引
+	mirrors Mirrors
 }
 
+// nodeDistURL Node.js官方发布目录，版本索引、安装包和校验文件都在其中
+const nodeDistURL = "https://nodejs.org/dist"
+
 // NewNodeSDK 创建一个新的Node.js SDK
 func NewNodeSDK() SDK {
 	provider := &NodeSDKProvider{
 		config: nil, // 这里为空，会由BaseSDK初始化时设置
+		mirrors: Mirrors{
+			Metadata: NewMirror(nodeDistURL),
+			Archives: NewMirror(nodeDistURL),
+		},
 	}
 
 	return &nodeSDK{
@@ -50,75 +60,58 @@ func (s *nodeSDK) GetCurrentVersion() (string, error) {
 	return version, nil
 }
 
-// GetVersionList 实现SDKProvider接口，获取所有可用的Node.js版本
-func (p *NodeSDKProvider) GetVersionList() ([]string, error) {
-	// 从Node.js官网获取版本列表
-	resp, err := http.Get("https://nodejs.org/dist/index.json")
+// GetReleases 实现SDKProvider接口，获取每个主版本的最新Node.js版本
+func (p *NodeSDKProvider) GetReleases() ([]Release, error) {
+	releases, err := p.GetAllReleases()
 	if err != nil {
-		return nil, fmt.Errorf("获取版本列表失败: %w", err)
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("读取响应失败: %w", err)
-	}
+	// 按主版本分组（例如：v12.9.1 -> v12），只保留最新版本
+	return latestReleasePerGroup(releases, majorGroup), nil
+}
 
-	var versions []NodeVersion
-	if err := json.Unmarshal(body, &versions); err != nil {
-		return nil, fmt.Errorf("解析版本列表失败: %w", err)
+// GetAllReleases 实现SDKProvider接口，获取所有可用的Node.js版本（不过滤）
+func (p *NodeSDKProvider) GetAllReleases() ([]Release, error) {
+	versions, err := p.fetchIndex()
+	if err != nil {
+		return nil, err
 	}
 
-	// 提取版本号，并按主版本分组
-	majorVersions := make(map[string]string)
+	var releases []Release
 	for _, v := range versions {
-		// 提取版本号
-		versionStr := v.Version
-
-		// 提取主版本号（例如：v12.9.1 -> v12）
-		parts := strings.Split(strings.TrimPrefix(versionStr, "v"), ".")
-		if len(parts) >= 1 {
-			majorVersion := "v" + parts[0]
-
-			// 如果该主版本尚未记录或当前版本更新，则更新
-			if currentVersion, exists := majorVersions[majorVersion]; !exists {
-				majorVersions[majorVersion] = versionStr
-			} else {
-				// 使用utils.CompareVersionsStr函数进行版本比较
-				v1 := strings.TrimPrefix(versionStr, "v")
-				v2 := strings.TrimPrefix(currentVersion, "v")
-
-				if utils.CompareVersionsStr(v1, v2) > 0 {
-					majorVersions[majorVersion] = versionStr
-				}
-			}
+		release := Release{
+			Version:  v.Version,
+			Date:     v.Date,
+			Security: v.Security,
+		}
+		if codename, ok := v.LTS.(string); ok {
+			release.LTS = codename
 		}
-	}
 
-	// 将主版本映射转换为版本列表
-	var versionList []string
-	for _, version := range majorVersions {
-		versionList = append(versionList, version)
+		for _, id := range v.Files {
+			release.Files = append(release.Files, nodeReleaseFiles(v.Version, id)...)
+		}
+
+		releases = append(releases, release)
 	}
 
 	// 按版本号排序（从新到旧）
-	utils.SortVersionsDesc(versionList)
+	SortReleasesDesc(releases)
 
-	return versionList, nil
+	return releases, nil
 }
 
-// GetAllVersionList 实现SDKProvider接口，获取所有可用的Node.js版本（不过滤）
-func (p *NodeSDKProvider) GetAllVersionList() ([]string, error) {
-	// 从Node.js官网获取版本列表
-	resp, err := http.Get("https://nodejs.org/dist/index.json")
-	if err != nil {
-		return nil, fmt.Errorf("获取版本列表失败: %w", err)
+// fetchIndex 获取Node.js官网的版本索引，同一进程内只请求一次
+func (p *NodeSDKProvider) fetchIndex() ([]NodeVersion, error) {
+	if p.index != nil {
+		return p.index, nil
 	}
-	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
+	// 从Node.js官网（或镜像）获取版本列表
+	body, err := p.mirrors.Metadata.Fetch(nodeDistURL + "/index.json")
 	if err != nil {
-		return nil, fmt.Errorf("读取响应失败: %w", err)
+		return nil, fmt.Errorf("获取版本列表失败: %w", err)
 	}
 
 	var versions []NodeVersion
@@ -126,20 +119,56 @@ func (p *NodeSDKProvider) GetAllVersionList() ([]string, error) {
 		return nil, fmt.Errorf("解析版本列表失败: %w", err)
 	}
 
-	// 提取所有版本号
-	var versionList []string
-	for _, v := range versions {
-		versionList = append(versionList, v.Version)
+	p.index = versions
+	return versions, nil
+}
+
+// nodeReleaseFiles 将index.json中的文件标识（如linux-x64、win-x64-zip、osx-arm64-tar）转换为下载文件
+func nodeReleaseFiles(version, id string) []ReleaseFile {
+	parts := strings.Split(id, "-")
+	if len(parts) < 2 {
+		return nil // headers、src 等不是二进制包
 	}
 
-	// 按版本号排序（从新到旧）
-	utils.SortVersionsDesc(versionList)
+	// 下载文件名中macOS使用darwin
+	osName, nameOS := parts[0], parts[0]
+	var exts []string
+	switch parts[0] {
+	case "win":
+		osName = "windows"
+		if len(parts) == 3 && parts[2] == "zip" {
+			exts = []string{"zip"}
+		}
+	case "osx":
+		osName, nameOS = "darwin", "darwin"
+		if len(parts) == 3 && parts[2] == "tar" {
+			exts = []string{"tar.gz", "tar.xz"}
+		}
+	case "linux", "aix", "sunos":
+		if len(parts) == 2 {
+			exts = []string{"tar.gz", "tar.xz"}
+		}
+	}
 
-	return versionList, nil
+	var files []ReleaseFile
+	for _, ext := range exts {
+		name := fmt.Sprintf("node-%s-%s-%s.%s", version, nameOS, parts[1], ext)
+		files = append(files, ReleaseFile{
+			Name:          name,
+			URL:           fmt.Sprintf("%s/%s/%s", nodeDistURL, version, name),
+			OS:            osName,
+			Arch:          parts[1],
+			ChecksumURL:   nodeChecksumURL(version),
+			SignatureURLs: nodeSignatureURLs(version),
+		})
+	}
+	return files
 }
 
 // GetDownloadURL 构建Node.js下载URL
-func (p *NodeSDKProvider) GetDownloadURL(version, osName, arch string) string {
+func (p *NodeSDKProvider) GetDownloadURL(release Release, osName, arch string) (ReleaseFile, error) {
+	version := release.Version
+
 	// 根据操作系统调整名称
 	if osName == "windows" {
 		osName = "win"
@@ -149,9 +178,80 @@ func (p *NodeSDKProvider) GetDownloadURL(version, osName, arch string) string {
 		osName = "linux"
 	}
 
-	// 构建ZIP文件名和下载URL
-	zipFileName := fmt.Sprintf("node-%s-%s-%s.zip", version, osName, arch)
-	return fmt.Sprintf("https://nodejs.org/dist/%s/%s", version, zipFileName)
+	// Windows只提供zip包，Linux和macOS优先使用体积更小的tar.xz，没有时使用tar.gz
+	exts := []string{"tar.xz", "tar.gz"}
+	if osName == "win" {
+		exts = []string{"zip"}
+	}
+
+	var fileName string
+	for _, ext := range exts {
+		fileName = fmt.Sprintf("node-%s-%s-%s.%s", version, osName, arch, ext)
+		if file, ok := release.FindFile(fileName); ok {
+			return file, nil
+		}
+	}
+
+	// 版本列表中没有对应文件时按命名规则构建，tar.gz 所有版本都提供
+	return ReleaseFile{
+		Name:          fileName,
+		URL:           fmt.Sprintf("%s/%s/%s", nodeDistURL, version, fileName),
+		ChecksumURL:   nodeChecksumURL(version),
+		SignatureURLs: nodeSignatureURLs(version),
+	}, nil
+}
+
+// nodeChecksumURL 返回Node.js版本的SHASUMS256.txt地址
+func nodeChecksumURL(version string) string {
+	return fmt.Sprintf("%s/%s/SHASUMS256.txt", nodeDistURL, version)
+}
+
+// nodeSignatureURLs 返回SHASUMS256.txt的签名地址：分离签名 .sig 和明文签名 .asc
+func nodeSignatureURLs(version string) []string {
+	checksumURL := nodeChecksumURL(version)
+	return []string{checksumURL + ".sig", checksumURL + ".asc"}
+}
+
+// nodeReleaseKeysURL Node.js发布团队维护的公钥仓库，keys.list 列出当前有效公钥的指纹
+const nodeReleaseKeysURL = "https://raw.githubusercontent.com/nodejs/release-keys/HEAD"
+
+// nodeReleaseKeys 内置的Node.js发布团队公钥，使用 go generate 从 nodejs/release-keys 更新，提交前需要审查指纹的变化
+//
+//go:generate go run ./keys/gen.go
+//go:embed keys/node.asc
+var nodeReleaseKeys []byte
+
+// DefaultKeyring 返回内置的Node.js发布团队公钥
+func (p *NodeSDKProvider) DefaultKeyring() []byte {
+	return nodeReleaseKeys
+}
+
+// FetchKeyring 从 nodejs/release-keys 获取Node.js发布团队当前的OpenPGP公钥
+func (p *NodeSDKProvider) FetchKeyring() ([]byte, error) {
+	list, err := utils.FetchBytes(nodeReleaseKeysURL + "/keys.list")
+	if err != nil {
+		return nil, fmt.Errorf("获取公钥列表失败: %w", err)
+	}
+
+	var keyring []byte
+	for _, fingerprint := range strings.Fields(string(list)) {
+		key, err := utils.FetchBytes(fmt.Sprintf("%s/keys/%s.asc", nodeReleaseKeysURL, fingerprint))
+		if err != nil {
+			return nil, fmt.Errorf("获取公钥 %s 失败: %w", fingerprint, err)
+		}
+		keyring = append(keyring, key...)
+		keyring = append(keyring, '\n')
+	}
+
+	if len(keyring) == 0 {
+		return nil, fmt.Errorf("公钥列表为空")
+	}
+	return keyring, nil
+}
+
+// GetMirrors 实现SDKProvider接口
+func (p *NodeSDKProvider) GetMirrors() *Mirrors {
+	return &p.mirrors
 }
 
 // GetExtractDir 获取解压后的目录名
@@ -214,18 +314,62 @@ func (p *NodeSDKProvider) PostInstall(version, installDir string) error {
 	return nil
 }
 
-// GetArchiveType 获取归档类型
-func (p *NodeSDKProvider) GetArchiveType() string {
-	return "zip"
+// IsInstallation 判断目录是否是Node.js安装目录
+func (p *NodeSDKProvider) IsInstallation(dir string) bool {
+	return findExecutable(dir, "node") != ""
 }
 
-// GetArchiveTypeForFile 根据文件名确定正确的归档类型
-func (p *NodeSDKProvider) GetArchiveTypeForFile(filePath string) string {
-	fileName := filepath.Base(filePath)
-	if strings.HasSuffix(fileName, ".zip") {
-		return "zip"
-	} else if strings.HasSuffix(fileName, ".tar.gz") || strings.HasSuffix(fileName, ".tgz") {
-		return "tar.gz"
+// DiscoverInstallations 在常见位置查找已有的Node.js，包括nvm和Volta安装的版本
+func (p *NodeSDKProvider) DiscoverInstallations() []Installation {
+	var installations []Installation
+	for _, source := range []struct {
+		prefix   string
+		patterns []string
+	}{
+		{"nvm", []string{"~/.nvm/versions/node/*"}},
+		{"volta", []string{"~/.volta/tools/image/node/*"}},
+		{"system", []string{"/usr/local", "/usr", "/opt/homebrew", `C:\Program Files\nodejs`}},
+	} {
+		for _, dir := range existingDirs(source.patterns...) {
+			executable := findExecutable(dir, "node")
+			if executable == "" {
+				continue
+			}
+
+			version := strings.TrimPrefix(filepath.Base(dir), "v")
+			if source.prefix == "system" {
+				if version = executableVersion(executable, "--version"); version == "" {
+					continue
+				}
+			}
+			installations = append(installations, Installation{Name: source.prefix + "-" + version, Dir: dir})
+		}
 	}
-	return "zip" // 默认为zip
+	return installations
+}
+
+// GetVersionFiles 获取Node.js生态的版本文件名
+func (p *NodeSDKProvider) GetVersionFiles() []string {
+	return []string{".nvmrc", ".node-version"}
+}
+
+// ParseVersionFile 解析.nvmrc或.node-version文件
+func (p *NodeSDKProvider) ParseVersionFile(filePath string) (string, error) {
+	version, err := readVersionFileLine(filePath)
+	if err != nil {
+		return "", err
+	}
+
+	// nvm 使用这些别名表示最新版本
+	switch strings.ToLower(version) {
+	case "node", "stable", "latest", "current":
+		return "latest", nil
+	}
+
+	// lts/* 或 lts/<代号> 保持原样，交给版本匹配处理
+	if strings.HasPrefix(strings.ToLower(version), "lts/") {
+		return strings.ToLower(version), nil
+	}
+
+	return version, nil
 }
@@ -0,0 +1,16 @@
+package sdk
+
+import (
+	"svm/internal/utils"
+	"testing"
+)
+
+func TestNodeDefaultKeyring(t *testing.T) {
+	keyring, err := utils.ParseKeyring((&NodeSDKProvider{}).DefaultKeyring())
+	if err != nil {
+		t.Fatalf("内置的Node.js发布方公钥无效，请在 internal/sdk 目录中运行 go generate: %v", err)
+	}
+	if len(keyring) == 0 {
+		t.Fatal("内置的Node.js发布方公钥为空")
+	}
+}
@@ -1,11 +1,12 @@
 package sdk
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
-	"net/http"
 	"os"
 	"os/exec"
+	"path"
 	"path/filepath"
 	"regexp"
 	"runtime"
@@ -23,13 +24,28 @@ type PythonVersion struct {
 
 // PythonSDKProvider 实现了SDKProvider接口
 type PythonSDKProvider struct {
-	config *config.Config
+	config        *config.Config
+	versions      []string // 已获取的版本号
+	mirrors       Mirrors
+	installTarget string // 最终的安装目录，编译源码时作为 --prefix
+	skipChecksum  bool   // 发布API中没有校验值时继续安装，不校验下载的文件
 }
 
+const (
+	// pythonFTPURL Python官方发布目录，版本目录和安装包都在其中
+	pythonFTPURL = "https://www.python.org/ftp/python"
+	// pythonAPIURL python.org的发布API，提供每个安装包的SHA-256
+	pythonAPIURL = "https://www.python.org/api/v2/downloads"
+)
+
 // NewPythonSDK 创建一个新的Python SDK
 func NewPythonSDK() SDK {
 	provider := &PythonSDKProvider{
 		config: nil, // 这里为空，会由BaseSDK初始化时设置
+		mirrors: Mirrors{
+			Metadata: NewMirror(pythonFTPURL),
+			Archives: NewMirror(pythonFTPURL),
+		},
 	}
 
 	return &pythonSDK{
@@ -42,26 +58,50 @@ type pythonSDK struct {
 	BaseSDK
 }
 
-// GetVersionList 实现SDKProvider接口，获取所有可用的Python版本
-func (p *PythonSDKProvider) GetVersionList() ([]string, error) {
-	// 直接从Python官方FTP目录获取版本列表
-	ftpUrl := "https://www.python.org/ftp/python/"
+// SetSkipChecksum 设置发布API中没有校验值时是否继续安装
+func (p *pythonSDK) SetSkipChecksum(skip bool) {
+	p.Provider.(*PythonSDKProvider).skipChecksum = skip
+}
 
-	// 获取目录列表
-	resp, err := http.Get(ftpUrl)
+// GetReleases 实现SDKProvider接口，获取每个次版本的最新Python版本
+func (p *PythonSDKProvider) GetReleases() ([]Release, error) {
+	releases, err := p.GetAllReleases()
 	if err != nil {
-		return nil, fmt.Errorf("获取Python版本列表失败: %w", err)
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("获取Python版本列表失败，HTTP状态码: %d", resp.StatusCode)
+	// 按次版本分组（例如：3.10.0 -> 3.10），只保留最新版本
+	return latestReleasePerGroup(releases, minorGroup), nil
+}
+
+// GetAllReleases 实现SDKProvider接口，获取所有可用的Python版本（不过滤）
+func (p *PythonSDKProvider) GetAllReleases() ([]Release, error) {
+	versions, err := p.fetchVersions()
+	if err != nil {
+		return nil, err
 	}
 
-	// 读取响应内容
-	body, err := io.ReadAll(resp.Body)
+	var releases []Release
+	for _, version := range versions {
+		releases = append(releases, Release{Version: version})
+	}
+
+	// 按版本号排序（从新到旧）
+	SortReleasesDesc(releases)
+
+	return releases, nil
+}
+
+// fetchVersions 从Python官方FTP目录获取版本号，同一进程内只请求一次
+func (p *PythonSDKProvider) fetchVersions() ([]string, error) {
+	if p.versions != nil {
+		return p.versions, nil
+	}
+
+	// 直接从Python官方FTP目录（或镜像）获取版本列表
+	body, err := p.mirrors.Metadata.Fetch(pythonFTPURL + "/")
 	if err != nil {
-		return nil, fmt.Errorf("读取响应内容失败: %w", err)
+		return nil, fmt.Errorf("获取Python版本列表失败: %w", err)
 	}
 
 	// 解析HTML内容，提取版本目录
@@ -78,110 +118,97 @@ func (p *PythonSDKProvider) GetVersionList() ([]string, error) {
 		matches = versionRegex.FindAllStringSubmatch(bodyStr, -1)
 	}
 
-	// 按次版本分组，每个次版本只保留最新的版本
-	minorVersions := make(map[string]string)
-
+	// 提取所有版本号
+	var versionList []string
 	for _, match := range matches {
 		if len(match) > 1 {
-			version := match[1]
-
-			// 提取次版本号（例如：3.10.0 -> 3.10）
-			parts := strings.Split(version, ".")
-			if len(parts) >= 2 {
-				minorVersion := parts[0] + "." + parts[1]
-
-				// 如果该次版本尚未记录或当前版本更新，则更新
-				if currentVersion, exists := minorVersions[minorVersion]; !exists {
-					minorVersions[minorVersion] = version
-				} else {
-					// 使用utils.compareVersions函数进行版本比较
-					if utils.CompareVersionsStr(version, currentVersion) > 0 {
-						minorVersions[minorVersion] = version
-					}
-				}
-			} else {
-				// 对于格式不符合预期的版本，直接添加
-				minorVersions[version] = version
-			}
+			versionList = append(versionList, match[1])
 		}
 	}
 
 	// 如果没有找到版本，返回错误
-	if len(minorVersions) == 0 {
+	if len(versionList) == 0 {
 		return nil, fmt.Errorf("未找到Python版本")
 	}
 
-	// 将次版本映射转换为版本列表
-	var versionList []string
-	for _, version := range minorVersions {
-		versionList = append(versionList, version)
-	}
-
-	// 按版本号排序（从新到旧）
-	utils.SortVersionsDesc(versionList)
-
+	p.versions = versionList
 	return versionList, nil
 }
 
-// GetAllVersionList 实现SDKProvider接口，获取所有可用的Python版本（不过滤）
-func (p *PythonSDKProvider) GetAllVersionList() ([]string, error) {
-	// 直接从Python官方FTP目录获取版本列表
-	ftpUrl := "https://www.python.org/ftp/python/"
-
-	// 获取目录列表
-	resp, err := http.Get(ftpUrl)
-	if err != nil {
-		return nil, fmt.Errorf("获取Python版本列表失败: %w", err)
+// GetDownloadURL 构建Python下载URL
+func (p *PythonSDKProvider) GetDownloadURL(release Release, osName, arch string) (ReleaseFile, error) {
+	url := p.getDownloadURL(release.Version, osName, arch)
+	file := ReleaseFile{
+		Name: path.Base(url),
+		URL:  url,
+		OS:   osName,
+		Arch: arch,
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("获取Python版本列表失败，HTTP状态码: %d", resp.StatusCode)
+	// FTP目录中没有校验文件，从python.org的发布API中查找SHA-256
+	checksum, err := p.getFileChecksum(release.Version, url)
+	if err == nil && checksum == "" {
+		err = fmt.Errorf("发布API中没有 %s 的校验值", file.Name)
 	}
-
-	// 读取响应内容
-	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("读取响应内容失败: %w", err)
+		if !p.skipChecksum {
+			return file, fmt.Errorf("获取 %s 的校验值失败: %w，如需在不校验的情况下安装，请使用 --skip-checksum", file.Name, err)
+		}
+		utils.Log.Warning(fmt.Sprintf("获取 %s 的校验值失败: %v，已跳过校验", file.Name, err))
+		return file, nil
 	}
 
-	// 解析HTML内容，提取版本目录
-	bodyStr := string(body)
-
-	// 使用正则表达式匹配版本目录
-	// 匹配形如 href="3.10.0/" 的目录链接
-	versionRegex := regexp.MustCompile(`href="(\d+\.\d+\.\d+)/"`)
-	matches := versionRegex.FindAllStringSubmatch(bodyStr, -1)
+	file.Checksum = checksum
+	file.ChecksumAlgorithm = "sha256"
+	return file, nil
+}
 
-	// 如果上面的正则表达式没有匹配到，尝试另一种格式
-	if len(matches) == 0 {
-		versionRegex = regexp.MustCompile(`>(\d+\.\d+\.\d+)/<`)
-		matches = versionRegex.FindAllStringSubmatch(bodyStr, -1)
+// getFileChecksum 从python.org的发布API中查找下载文件的SHA-256，未提供时返回空字符串
+// 发布API不在FTP目录的镜像中，通过元数据镜像获取时使用官方地址，但会缓存结果
+func (p *PythonSDKProvider) getFileChecksum(version, fileURL string) (string, error) {
+	// 根据版本名称查找发布ID
+	data, err := p.mirrors.Metadata.Fetch(fmt.Sprintf("%s/release/?name=Python%%20%s", pythonAPIURL, version))
+	if err != nil {
+		return "", err
 	}
 
-	// 提取所有版本号
-	var versionList []string
-	for _, match := range matches {
-		if len(match) > 1 {
-			versionList = append(versionList, match[1])
-		}
+	var releases []struct {
+		ResourceURI string `json:"resource_uri"`
+	}
+	if err := json.Unmarshal(data, &releases); err != nil {
+		return "", fmt.Errorf("解析发布信息失败: %w", err)
+	}
+	if len(releases) == 0 {
+		return "", fmt.Errorf("未找到Python %s 的发布信息", version)
 	}
+	releaseID := path.Base(strings.TrimSuffix(releases[0].ResourceURI, "/"))
 
-	// 如果没有找到版本，返回错误
-	if len(versionList) == 0 {
-		return nil, fmt.Errorf("未找到Python版本")
+	// 获取发布中的文件列表
+	data, err = p.mirrors.Metadata.Fetch(fmt.Sprintf("%s/release_file/?release=%s", pythonAPIURL, releaseID))
+	if err != nil {
+		return "", err
 	}
 
-	// 按版本号排序（从新到旧）
-	utils.SortVersionsDesc(versionList)
+	var files []struct {
+		URL       string `json:"url"`
+		SHA256Sum string `json:"sha256_sum"`
+	}
+	if err := json.Unmarshal(data, &files); err != nil {
+		return "", fmt.Errorf("解析文件列表失败: %w", err)
+	}
 
-	return versionList, nil
+	for _, file := range files {
+		if path.Base(file.URL) == path.Base(fileURL) {
+			return file.SHA256Sum, nil
+		}
+	}
+	return "", nil
 }
 
-// GetDownloadURL 构建Python下载URL
-func (p *PythonSDKProvider) GetDownloadURL(version, osName, arch string) string {
+// getDownloadURL 根据操作系统和架构选择Python安装包
+func (p *PythonSDKProvider) getDownloadURL(version, osName, arch string) string {
 	// 根据操作系统和架构构建下载URL
-	baseUrl := "https://www.python.org/ftp/python"
+	baseUrl := pythonFTPURL
 
 	// 尝试不同的下载格式
 	if osName == "windows" {
@@ -197,48 +224,44 @@ func (p *PythonSDKProvider) GetDownloadURL(version, osName, arch string) string
 		basePath := fmt.Sprintf("%s/%s", baseUrl, version)
 
 		// 尝试获取目录列表
-		resp, err := http.Get(basePath + "/")
-		if err == nil && resp.StatusCode == http.StatusOK {
-			defer resp.Body.Close()
-			body, err := io.ReadAll(resp.Body)
-			if err == nil {
-				bodyStr := string(body)
-
-				// 查找所有zip文件链接
-				zipRegex := regexp.MustCompile(`href="([^"]+\.zip)"`)
-				matches := zipRegex.FindAllStringSubmatch(bodyStr, -1)
-
-				// 优先选择非嵌入式版本
-				var regularZip string
-				var embedZip string
-
-				for _, match := range matches {
-					if len(match) > 1 {
-						fileName := match[1]
-
-						// 检查是否包含架构后缀
-						if strings.Contains(fileName, archSuffix) {
-							fullUrl := fmt.Sprintf("%s/%s", basePath, fileName)
-
-							// 检查是否是嵌入式版本
-							if strings.Contains(fileName, "embed") {
-								embedZip = fullUrl
-							} else {
-								regularZip = fullUrl
-								break // 找到非嵌入式版本就停止
-							}
+		body, err := p.mirrors.Metadata.Fetch(basePath + "/")
+		if err == nil {
+			bodyStr := string(body)
+
+			// 查找所有zip文件链接
+			zipRegex := regexp.MustCompile(`href="([^"]+\.zip)"`)
+			matches := zipRegex.FindAllStringSubmatch(bodyStr, -1)
+
+			// 优先选择非嵌入式版本
+			var regularZip string
+			var embedZip string
+
+			for _, match := range matches {
+				if len(match) > 1 {
+					fileName := match[1]
+
+					// 检查是否包含架构后缀
+					if strings.Contains(fileName, archSuffix) {
+						fullUrl := fmt.Sprintf("%s/%s", basePath, fileName)
+
+						// 检查是否是嵌入式版本
+						if strings.Contains(fileName, "embed") {
+							embedZip = fullUrl
+						} else {
+							regularZip = fullUrl
+							break // 找到非嵌入式版本就停止
 						}
 					}
 				}
+			}
 
-				// 优先返回非嵌入式版本，如果没有则返回嵌入式版本
-				if regularZip != "" {
-					return regularZip
-				}
+			// 优先返回非嵌入式版本，如果没有则返回嵌入式版本
+			if regularZip != "" {
+				return regularZip
+			}
 
-				if embedZip != "" {
-					return embedZip
-				}
+			if embedZip != "" {
+				return embedZip
 			}
 		}
 
@@ -247,15 +270,13 @@ func (p *PythonSDKProvider) GetDownloadURL(version, osName, arch string) string
 		regularUrl := fmt.Sprintf("%s/%s/python-%s%s.zip", baseUrl, version, version, archSuffix)
 
 		// 检查常规URL是否存在
-		exists, _ := utils.CheckURLExists(regularUrl)
-		if exists {
+		if p.archiveExists(regularUrl) {
 			return regularUrl
 		}
 
 		// 如果常规格式不存在，尝试嵌入式格式
 		embedUrl := fmt.Sprintf("%s/%s/python-%s-embed%s.zip", baseUrl, version, version, archSuffix)
-		exists, _ = utils.CheckURLExists(embedUrl)
-		if exists {
+		if p.archiveExists(embedUrl) {
 			return embedUrl
 		}
 
@@ -273,6 +294,21 @@ func (p *PythonSDKProvider) GetDownloadURL(version, osName, arch string) string
 	}
 }
 
+// archiveExists 检查安装包在任一镜像中是否存在
+func (p *PythonSDKProvider) archiveExists(url string) bool {
+	for _, mirrorURL := range p.mirrors.Archives.URLs(url) {
+		if exists, _ := utils.CheckURLExists(mirrorURL); exists {
+			return true
+		}
+	}
+	return false
+}
+
+// GetMirrors 实现SDKProvider接口
+func (p *PythonSDKProvider) GetMirrors() *Mirrors {
+	return &p.mirrors
+}
+
 // GetExtractDir 获取解压后的目录名
 func (p *PythonSDKProvider) GetExtractDir(version, downloadedFile string) string {
 	if runtime.GOOS == "linux" {
@@ -294,6 +330,42 @@ func (p *PythonSDKProvider) GetBinDir(baseDir string) string {
 	return baseDir
 }
 
+// IsInstallation 判断目录是否是Python安装目录
+func (p *PythonSDKProvider) IsInstallation(dir string) bool {
+	return findExecutable(dir, "python3", "python") != ""
+}
+
+// DiscoverInstallations 在常见位置查找已有的Python，包括pyenv安装的版本
+func (p *PythonSDKProvider) DiscoverInstallations() []Installation {
+	var installations []Installation
+	for _, source := range []struct {
+		prefix   string
+		patterns []string
+	}{
+		{"pyenv", []string{"~/.pyenv/versions/*"}},
+		{"system", []string{
+			"/usr/local", "/usr", "/opt/homebrew", "/Library/Frameworks/Python.framework/Versions/*",
+			`~\AppData\Local\Programs\Python\Python*`, `C:\Python*`,
+		}},
+	} {
+		for _, dir := range existingDirs(source.patterns...) {
+			executable := findExecutable(dir, "python3", "python")
+			if executable == "" {
+				continue
+			}
+
+			version := filepath.Base(dir)
+			if source.prefix == "system" {
+				if version = executableVersion(executable, "--version"); version == "" {
+					continue
+				}
+			}
+			installations = append(installations, Installation{Name: source.prefix + "-" + version, Dir: dir})
+		}
+	}
+	return installations
+}
+
 // ConfigureEnv 配置环境变量
 func (p *PythonSDKProvider) ConfigureEnv(version, installDir string) ([]config.EnvVar, error) {
 	// 添加Python主目录和Scripts目录到PATH
@@ -380,7 +452,7 @@ func (p *PythonSDKProvider) PostInstall(version, installDir string) error {
 		getPipPath := filepath.Join(installDir, "get-pip.py")
 
 		utils.Log.Install("下载get-pip.py...")
-		resp, err := http.Get(getPipURL)
+		resp, err := utils.HTTPGet(getPipURL)
 		if err != nil {
 			utils.Log.Warning(fmt.Sprintf("下载get-pip.py失败: %v", err))
 			return nil
@@ -524,9 +596,17 @@ func (p *PythonSDKProvider) PostInstall(version, installDir string) error {
 		// 对于Linux，我们需要编译源码
 		extractDir := filepath.Join(installDir, fmt.Sprintf("Python-%s", version))
 		if _, err := os.Stat(extractDir); err == nil {
+			// 安装在临时目录中进行，--prefix 使用最终的安装目录，脚本中的路径才正确，
+			// 编译结果通过 DESTDIR 先安装到临时目录中
+			prefix := installDir
+			if p.installTarget != "" {
+				prefix = p.installTarget
+			}
+			destDir := filepath.Join(installDir, ".destdir")
+
 			// 编译源码
-			configureCmd := fmt.Sprintf(`cd "%s" && ./configure --prefix="%s" && make && make install`,
-				extractDir, installDir)
+			configureCmd := fmt.Sprintf(`cd "%s" && ./configure --prefix="%s" && make && make install DESTDIR="%s"`,
+				extractDir, prefix, destDir)
 			utils.Log.Install(fmt.Sprintf("正在编译Python: %s", configureCmd))
 			cmd := exec.Command("bash", "-c", configureCmd)
 			output, err := cmd.CombinedOutput()
@@ -534,6 +614,13 @@ func (p *PythonSDKProvider) PostInstall(version, installDir string) error {
 				return fmt.Errorf("编译Python失败: %w\n%s", err, string(output))
 			}
 
+			if err := moveDirContents(filepath.Join(destDir, prefix), installDir); err != nil {
+				return fmt.Errorf("移动编译结果失败: %w", err)
+			}
+			if err := os.RemoveAll(destDir); err != nil {
+				utils.Log.Warning(fmt.Sprintf("警告：删除临时目录失败: %v", err))
+			}
+
 			utils.Log.Success("Python编译和安装完成")
 
 			// 删除源码目录
@@ -546,26 +633,47 @@ func (p *PythonSDKProvider) PostInstall(version, installDir string) error {
 	return nil
 }
 
-// GetArchiveType 获取归档类型
-func (p *PythonSDKProvider) GetArchiveType() string {
-	// 对于Python，我们根据操作系统和具体的下载文件来决定归档类型
-	// 这个方法将在实际下载文件后由调用方通过 GetArchiveTypeForFile 获取具体类型
-	return "auto"
+// SetInstallTarget 设置最终的安装目录，编译源码时写入 --prefix
+func (p *PythonSDKProvider) SetInstallTarget(dir string) {
+	p.installTarget = dir
+}
+
+// moveDirContents 将目录中的所有文件移动到目标目录
+func moveDirContents(srcDir, dstDir string) error {
+	entries, err := os.ReadDir(srcDir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if err := os.Rename(filepath.Join(srcDir, entry.Name()), filepath.Join(dstDir, entry.Name())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetVersionFiles 获取Python生态的版本文件名
+func (p *PythonSDKProvider) GetVersionFiles() []string {
+	return []string{".python-version"}
 }
 
-// GetArchiveTypeForFile 根据文件名确定正确的归档类型
-func (p *PythonSDKProvider) GetArchiveTypeForFile(filePath string) string {
-	// 根据文件扩展名判断
-	fileName := filepath.Base(filePath)
-	if strings.HasSuffix(fileName, ".zip") {
-		return "zip"
-	} else if strings.HasSuffix(fileName, ".tar.gz") || strings.HasSuffix(fileName, ".tgz") {
-		return "tar.gz"
-	} else if strings.HasSuffix(fileName, ".exe") || strings.HasSuffix(fileName, ".msi") {
-		// 可执行安装程序
-		return "none"
-	}
-
-	// 默认情况下尝试作为zip处理
-	return "zip"
+// ParseVersionFile 解析pyenv的.python-version文件
+// 文件中可能列出多个版本，只使用第一个
+func (p *PythonSDKProvider) ParseVersionFile(filePath string) (string, error) {
+	version, err := readVersionFileLine(filePath)
+	if err != nil {
+		return "", err
+	}
+
+	version = strings.TrimPrefix(strings.Fields(version)[0], "cpython-")
+	if version == "system" {
+		return "", fmt.Errorf("不支持使用系统Python（system）")
+	}
+
+	// 只支持CPython，例如pypy3.9-7.3.9、miniconda3-latest等无法安装
+	if version == "" || version[0] < '0' || version[0] > '9' {
+		return "", fmt.Errorf("不支持的Python实现: %s", version)
+	}
+
+	return version, nil
 }
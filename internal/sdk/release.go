@@ -0,0 +1,132 @@
+package sdk
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"svm/internal/utils"
+)
+
+// Release 表示一个可安装的SDK版本及其元数据
+type Release struct {
+	Version      string        // 版本号，与安装目录名一致（例如Node.js的v18.20.4）
+	Date         string        // 发布日期（YYYY-MM-DD），未知时为空
+	LTS          string        // LTS代号（如Node.js的Iron），没有代号的LTS版本为"LTS"，非LTS版本为空
+	Security     bool          // 是否包含安全修复
+	SupportPhase string        // 支持阶段，例如 active、maintenance、preview、eol
+	Files        []ReleaseFile // 可下载的文件，未知时为空
+}
+
+// ReleaseFile 表示版本中的一个可下载文件
+type ReleaseFile struct {
+	Name              string   // 文件名
+	URL               string   // 下载地址
+	OS                string   // 操作系统：windows、darwin、linux，未知时为空
+	Arch              string   // 架构：x64、x86、arm64、arm，未知时为空
+	Size              int64    // 文件大小（字节），未知时为0
+	Checksum          string   // 校验值（十六进制）
+	ChecksumAlgorithm string   // 校验算法，例如 sha256、sha512
+	ChecksumURL       string   // sha256sum 格式的校验文件地址（如Node.js的SHASUMS256.txt），Checksum为空时从中查找
+	SignatureURLs     []string // 校验文件的OpenPGP签名地址（分离签名或明文签名），按顺序尝试，为空时不验证签名
+}
+
+// IsLTS 判断是否为长期支持版本
+func (r Release) IsLTS() bool {
+	return r.LTS != ""
+}
+
+// FindFile 查找指定名称的文件
+func (r Release) FindFile(name string) (ReleaseFile, bool) {
+	for _, file := range r.Files {
+		if file.Name == name {
+			return file, true
+		}
+	}
+	return ReleaseFile{}, false
+}
+
+// String 返回版本号及主要元数据，用于列表显示
+func (r Release) String() string {
+	var details []string
+	if r.Date != "" {
+		details = append(details, r.Date)
+	}
+	if r.LTS == "LTS" {
+		details = append(details, "LTS")
+	} else if r.LTS != "" {
+		details = append(details, "LTS: "+r.LTS)
+	}
+	if r.SupportPhase != "" {
+		details = append(details, r.SupportPhase)
+	}
+	if r.Security {
+		details = append(details, "安全更新")
+	}
+
+	if len(details) == 0 {
+		return r.Version
+	}
+	return fmt.Sprintf("%-16s %s", r.Version, strings.Join(details, "  "))
+}
+
+// SortReleasesDesc 按版本号降序排序
+func SortReleasesDesc(releases []Release) {
+	sort.SliceStable(releases, func(i, j int) bool {
+		return utils.CompareVersionsStr(releases[i].Version, releases[j].Version) > 0
+	})
+}
+
+// ReleaseVersions 提取版本号列表
+func ReleaseVersions(releases []Release) []string {
+	versions := make([]string, 0, len(releases))
+	for _, release := range releases {
+		versions = append(versions, release.Version)
+	}
+	return versions
+}
+
+// latestReleasePerGroup 按分组只保留最新的版本，并按版本号降序返回
+// group 返回版本所属的分组（例如主版本号），返回空字符串的版本会被丢弃
+func latestReleasePerGroup(releases []Release, group func(utils.Version) string) []Release {
+	latest := make(map[string]Release)
+	var groups []string
+	for _, release := range releases {
+		version, err := utils.ParseVersion(release.Version)
+		if err != nil {
+			continue
+		}
+
+		key := group(version)
+		if key == "" {
+			continue
+		}
+
+		current, exists := latest[key]
+		if !exists {
+			groups = append(groups, key)
+		}
+		if !exists || utils.CompareVersionsStr(release.Version, current.Version) > 0 {
+			latest[key] = release
+		}
+	}
+
+	result := make([]Release, 0, len(groups))
+	for _, key := range groups {
+		result = append(result, latest[key])
+	}
+	SortReleasesDesc(result)
+	return result
+}
+
+// majorGroup 按主版本号分组
+func majorGroup(v utils.Version) string {
+	return fmt.Sprintf("%d", v.Major())
+}
+
+// minorGroup 按次版本号分组，没有次版本号的版本不参与分组
+func minorGroup(v utils.Version) string {
+	if len(v.Segments()) < 2 {
+		return ""
+	}
+	return fmt.Sprintf("%d.%d", v.Major(), v.Minor())
+}
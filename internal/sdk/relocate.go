@@ -0,0 +1,221 @@
+package sdk
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"svm/internal/config"
+	"svm/internal/utils"
+)
+
+// 移动安装目录时使用的临时名称：复制到 <目标>.partial，校验后重命名到位；
+// 原目录先重命名为 <原目录>.moved 再删除，中断后重新运行可以识别已完成的步骤
+const (
+	relocatePartialSuffix = ".partial"
+	relocateMovedSuffix   = ".moved"
+)
+
+// RelocateInstallDir 将安装目录中的SDK和缓存移动到新的安装目录，然后更新配置中的路径。
+// names 为要移动的SDK，单独配置了安装目录的SDK保持不变。同一文件系统中直接重命名，否则复制并校验后删除原目录。
+// 中断后使用相同的参数重新运行即可继续；登记的外部目录保持不变
+func RelocateInstallDir(cfg *config.Config, newRoot string, names []string) error {
+	oldRoot := cfg.InstallDir
+	if filepath.Clean(oldRoot) == filepath.Clean(newRoot) {
+		return os.MkdirAll(newRoot, 0755)
+	}
+
+	// 单独配置了安装目录的SDK不在全局安装目录中
+	var entries []string
+	moved := make(map[string]bool)
+	for _, name := range names {
+		if cfg.SDKs[name].InstallDir == "" {
+			entries = append(entries, name)
+			moved[name] = true
+		}
+	}
+	entries = append(entries, "cache")
+	for _, name := range entries {
+		if src := filepath.Join(oldRoot, name); utils.IsWithin(src, newRoot) {
+			return fmt.Errorf("新的安装目录不能位于 %s 中", src)
+		}
+	}
+	if err := os.MkdirAll(newRoot, 0755); err != nil {
+		return fmt.Errorf("创建目录失败: %w", err)
+	}
+
+	for _, name := range entries {
+		src := filepath.Join(oldRoot, name)
+		dst := filepath.Join(newRoot, name)
+		if name != "cache" {
+			removeCurrentLinks(src)
+		}
+		if err := relocateEntry(src, dst); err != nil {
+			return fmt.Errorf("移动 %s 失败: %w", src, err)
+		}
+	}
+
+	// 所有目录就位后一次性更新配置，中断时配置仍指向原安装目录
+	return cfg.Update(func(c *config.Config) error {
+		rebase := func(path string) string {
+			if path == "" || !utils.IsWithin(oldRoot, path) {
+				return path
+			}
+			rel, _ := filepath.Rel(oldRoot, path)
+			return filepath.Join(newRoot, rel)
+		}
+
+		c.InstallDir = newRoot
+		for sdkName, sdkConfig := range c.SDKs {
+			// 缓存目录由所有SDK共用，安装目录和环境变量只更新已移动的SDK
+			for version, info := range sdkConfig.VersionCache {
+				if moved[sdkName] && !info.Linked {
+					info.InstallDir = rebase(info.InstallDir)
+				}
+				info.CacheFilePath = rebase(info.CacheFilePath)
+				sdkConfig.VersionCache[version] = info
+			}
+			if !moved[sdkName] {
+				c.SDKs[sdkName] = sdkConfig
+				continue
+			}
+
+			// 环境变量的值可能是路径列表，例如 PATH
+			for i, env := range sdkConfig.EnvVars {
+				paths := filepath.SplitList(env.Value)
+				for j, path := range paths {
+					paths[j] = rebase(path)
+				}
+				sdkConfig.EnvVars[i].Value = strings.Join(paths, string(os.PathListSeparator))
+			}
+			c.SDKs[sdkName] = sdkConfig
+		}
+		return nil
+	})
+}
+
+// relocateEntry 将 src 移动到 dst，可以从上次中断的位置继续
+func relocateEntry(src, dst string) error {
+	moved := src + relocateMovedSuffix
+	partial := dst + relocatePartialSuffix
+
+	// 上次已经复制完成，只是没有删除原目录
+	if _, err := os.Lstat(moved); err == nil {
+		utils.Log.Delete(fmt.Sprintf("正在删除已移动的目录: %s", moved))
+		if err := os.RemoveAll(moved); err != nil {
+			return err
+		}
+	}
+
+	if _, err := os.Lstat(src); os.IsNotExist(err) {
+		return nil
+	}
+
+	if _, err := os.Lstat(dst); err == nil {
+		// 上次复制完成后在重命名原目录之前中断
+		if err := compareTrees(src, dst); err != nil {
+			return fmt.Errorf("目标 %s 已存在且与原目录不同: %w", dst, err)
+		}
+		return removeRelocated(src)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+
+	utils.Log.Move(fmt.Sprintf("正在移动 %s -> %s", src, dst))
+	if err := os.Rename(src, dst); err == nil {
+		return nil
+	}
+
+	// 不在同一文件系统中，复制后校验
+	if err := os.RemoveAll(partial); err != nil {
+		return err
+	}
+	if err := utils.CopyDir(src, partial); err != nil {
+		return fmt.Errorf("复制失败: %w", err)
+	}
+	if err := compareTrees(src, partial); err != nil {
+		return fmt.Errorf("校验复制的文件失败: %w", err)
+	}
+	if err := os.Rename(partial, dst); err != nil {
+		return err
+	}
+	return removeRelocated(src)
+}
+
+// removeRelocated 删除已经复制到新位置的原目录，先重命名以便中断后识别
+func removeRelocated(src string) error {
+	moved := src + relocateMovedSuffix
+	if err := os.Rename(src, moved); err != nil {
+		return err
+	}
+	return os.RemoveAll(moved)
+}
+
+// removeCurrentLinks 删除SDK目录中指向版本目录的 current 链接，移动后重新创建。
+// .NET 的 current 位于各组件目录中。Windows上复制出的 current 目录保持不变
+func removeCurrentLinks(sdkDir string) {
+	links := []string{filepath.Join(sdkDir, "current")}
+	if matches, err := filepath.Glob(filepath.Join(sdkDir, "*", "current")); err == nil {
+		links = append(links, matches...)
+	}
+
+	for _, link := range links {
+		if info, err := os.Lstat(link); err == nil && !info.IsDir() {
+			os.Remove(link)
+		}
+	}
+}
+
+// compareTrees 比较两个目录中的文件、大小、权限、内容（SHA-256）和符号链接是否相同
+func compareTrees(src, dst string) error {
+	return filepath.WalkDir(src, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(src, path)
+		other := filepath.Join(dst, rel)
+
+		srcInfo, err := os.Lstat(path)
+		if err != nil {
+			return err
+		}
+		dstInfo, err := os.Lstat(other)
+		if err != nil {
+			return fmt.Errorf("缺少 %s", rel)
+		}
+
+		switch {
+		case srcInfo.Mode()&os.ModeSymlink != 0:
+			srcTarget, _ := os.Readlink(path)
+			dstTarget, _ := os.Readlink(other)
+			// 无法创建符号链接时复制了链接指向的内容
+			if dstInfo.Mode()&os.ModeSymlink != 0 && srcTarget != dstTarget {
+				return fmt.Errorf("%s 的链接目标不同", rel)
+			}
+		case srcInfo.IsDir():
+			if !dstInfo.IsDir() {
+				return fmt.Errorf("%s 不是目录", rel)
+			}
+		default:
+			if dstInfo.Size() != srcInfo.Size() || dstInfo.Mode().Perm() != srcInfo.Mode().Perm() {
+				return fmt.Errorf("%s 的大小或权限不同", rel)
+			}
+			// 大小相同的文件也可能在复制中损坏，删除原目录前比较内容
+			srcSum, err := utils.FileChecksum(path, "sha256")
+			if err != nil {
+				return err
+			}
+			dstSum, err := utils.FileChecksum(other, "sha256")
+			if err != nil {
+				return err
+			}
+			if srcSum != dstSum {
+				return fmt.Errorf("%s 的内容不同", rel)
+			}
+		}
+		return nil
+	})
+}
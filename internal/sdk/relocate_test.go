@@ -0,0 +1,175 @@
+package sdk
+
+import (
+	"os"
+	"path/filepath"
+	"svm/internal/config"
+	"svm/internal/utils"
+	"testing"
+)
+
+func TestRelocateInstallDir(t *testing.T) {
+	home := t.TempDir()
+	config.SetConfigFile(filepath.Join(home, "config.json"))
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	oldRoot := cfg.InstallDir
+	newRoot := filepath.Join(t.TempDir(), "svm")
+	customGo := filepath.Join(oldRoot, "custom-go")
+
+	// node 位于全局安装目录中，go 单独配置了位于原安装目录中的安装目录
+	for _, dir := range []string{
+		filepath.Join(oldRoot, "node", "18.20.4", "bin"),
+		filepath.Join(customGo, "1.22.3", "bin"),
+		filepath.Join(oldRoot, "cache", "node"),
+		filepath.Join(oldRoot, "cache", "go"),
+	} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, file := range []string{
+		filepath.Join(oldRoot, "cache", "node", "node.tar.gz"),
+		filepath.Join(oldRoot, "cache", "go", "go.tar.gz"),
+	} {
+		if err := os.WriteFile(file, []byte("archive"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	err = cfg.Update(func(c *config.Config) error {
+		c.SDKs["node"] = config.SDKConfig{
+			EnvVars: []config.EnvVar{{Key: "PATH", Value: filepath.Join(oldRoot, "node", "current", "bin")}},
+			VersionCache: map[string]config.SDKVersionInfo{
+				"18.20.4": {
+					InstallDir:    filepath.Join(oldRoot, "node", "18.20.4"),
+					CacheFilePath: filepath.Join(oldRoot, "cache", "node", "node.tar.gz"),
+				},
+			},
+		}
+		c.SDKs["go"] = config.SDKConfig{
+			InstallDir: customGo,
+			EnvVars:    []config.EnvVar{{Key: "GOROOT", Value: filepath.Join(customGo, "current")}},
+			VersionCache: map[string]config.SDKVersionInfo{
+				"1.22.3": {
+					InstallDir:    filepath.Join(customGo, "1.22.3"),
+					CacheFilePath: filepath.Join(oldRoot, "cache", "go", "go.tar.gz"),
+				},
+			},
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := RelocateInstallDir(cfg, newRoot, []string{"node", "go"}); err != nil {
+		t.Fatalf("RelocateInstallDir() 失败: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"安装目录", cfg.InstallDir, newRoot},
+		{"node 版本目录", cfg.SDKs["node"].VersionCache["18.20.4"].InstallDir, filepath.Join(newRoot, "node", "18.20.4")},
+		{"node 缓存文件", cfg.SDKs["node"].VersionCache["18.20.4"].CacheFilePath, filepath.Join(newRoot, "cache", "node", "node.tar.gz")},
+		{"node 环境变量", cfg.SDKs["node"].EnvVars[0].Value, filepath.Join(newRoot, "node", "current", "bin")},
+		{"go 安装目录", cfg.SDKs["go"].InstallDir, customGo},
+		{"go 版本目录", cfg.SDKs["go"].VersionCache["1.22.3"].InstallDir, filepath.Join(customGo, "1.22.3")},
+		{"go 缓存文件", cfg.SDKs["go"].VersionCache["1.22.3"].CacheFilePath, filepath.Join(newRoot, "cache", "go", "go.tar.gz")},
+		{"go 环境变量", cfg.SDKs["go"].EnvVars[0].Value, filepath.Join(customGo, "current")},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s = %s，期望 %s", tt.name, tt.got, tt.want)
+		}
+	}
+
+	for _, path := range []string{
+		filepath.Join(newRoot, "node", "18.20.4", "bin"),
+		filepath.Join(customGo, "1.22.3", "bin"),
+		filepath.Join(newRoot, "cache", "go", "go.tar.gz"),
+	} {
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("%s 不存在: %v", path, err)
+		}
+	}
+}
+
+func TestCompareTrees(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(t *testing.T, dst string)
+		wantErr bool
+	}{
+		{name: "相同", modify: func(t *testing.T, dst string) {}},
+		{
+			name: "大小相同但内容不同",
+			modify: func(t *testing.T, dst string) {
+				writeTestFile(t, filepath.Join(dst, "bin", "go"), "GO")
+			},
+			wantErr: true,
+		},
+		{
+			name: "文件被截断",
+			modify: func(t *testing.T, dst string) {
+				writeTestFile(t, filepath.Join(dst, "VERSION"), "go1")
+			},
+			wantErr: true,
+		},
+		{
+			name: "缺少文件",
+			modify: func(t *testing.T, dst string) {
+				os.Remove(filepath.Join(dst, "VERSION"))
+			},
+			wantErr: true,
+		},
+		{
+			name: "链接目标不同",
+			modify: func(t *testing.T, dst string) {
+				os.Remove(filepath.Join(dst, "current"))
+				if err := os.Symlink("VERSION", filepath.Join(dst, "current")); err != nil {
+					t.Fatal(err)
+				}
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := filepath.Join(t.TempDir(), "src")
+			writeTestFile(t, filepath.Join(src, "bin", "go"), "go")
+			writeTestFile(t, filepath.Join(src, "VERSION"), "go1.22.3")
+			if err := os.Symlink("bin", filepath.Join(src, "current")); err != nil {
+				t.Fatal(err)
+			}
+
+			dst := filepath.Join(t.TempDir(), "dst")
+			if err := utils.CopyDir(src, dst); err != nil {
+				t.Fatal(err)
+			}
+			tt.modify(t, dst)
+
+			if err := compareTrees(src, dst); (err != nil) != tt.wantErr {
+				t.Errorf("compareTrees() = %v，期望返回错误: %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// writeTestFile 创建文件及其所在的目录
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
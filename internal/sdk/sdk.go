@@ -1,23 +1,29 @@
 package sdk
 
 import (
+	"errors"
 	"fmt"
+	"net/url"
 	"os"
 	"os/exec"
+	"path"
 	"path/filepath"
 	"runtime"
 	"strings"
 	"svm/internal/config"
 	"svm/internal/utils"
+	"time"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
 )
 
 // SDK 定义了所有语言SDK需要实现的接口
 type SDK interface {
-	// List 列出所有可用版本
-	List() ([]string, error)
+	// List 列出常用的可用版本
+	List() ([]Release, error)
 
 	// ListAll 列出所有可用版本（不过滤）
-	ListAll() ([]string, error)
+	ListAll() ([]Release, error)
 
 	// Install 安装指定版本
 	Install(version string) error
@@ -36,18 +42,22 @@ type SDK interface {
 
 	// SetupEnv 设置环境变量
 	SetupEnv(version string) error
+
+	// ResolveVersionFile 在指定目录中查找生态原生的版本文件并解析出版本请求
+	// 未找到版本文件时返回空的文件路径
+	ResolveVersionFile(dir string) (version string, filePath string, err error)
 }
 
 // SDKProvider 定义了SDK的基本行为
 type SDKProvider interface {
-	// GetVersionList 获取可用版本列表
-	GetVersionList() ([]string, error)
+	// GetReleases 获取常用的可用版本（例如每个主版本的最新版本），按版本号降序排列
+	GetReleases() ([]Release, error)
 
-	// GetAllVersionList 获取所有可用版本列表（不过滤）
-	GetAllVersionList() ([]string, error)
+	// GetAllReleases 获取所有可用版本（不过滤），按版本号降序排列
+	GetAllReleases() ([]Release, error)
 
-	// GetDownloadURL 获取下载URL
-	GetDownloadURL(version, osName, arch string) string
+	// GetDownloadURL 从版本元数据中选择适用于指定平台的下载文件
+	GetDownloadURL(release Release, osName, arch string) (ReleaseFile, error)
 
 	// GetExtractDir 获取解压后的目录名
 	GetExtractDir(version, downloadedFile string) string
@@ -64,11 +74,29 @@ type SDKProvider interface {
 	// PostInstall 安装后的处理工作
 	PostInstall(version, installDir string) error
 
-	// GetArchiveType 获取归档类型
-	GetArchiveType() string
+	// GetVersionFiles 获取生态原生的版本文件名（按优先级排列）
+	GetVersionFiles() []string
+
+	// ParseVersionFile 解析生态原生的版本文件，返回版本请求
+	ParseVersionFile(filePath string) (string, error)
+
+	// GetMirrors 获取版本元数据和下载文件的官方地址及镜像配置
+	GetMirrors() *Mirrors
+}
+
+// KeyringProvider 由发布校验文件签名的SDK实现，提供验证签名使用的发布方公钥
+type KeyringProvider interface {
+	// DefaultKeyring 返回内置的发布方OpenPGP公钥，本地没有公钥环时使用
+	DefaultKeyring() []byte
+
+	// FetchKeyring 从发布方获取当前的OpenPGP公钥（ASCII armor格式），只在用户明确要求更新时使用
+	FetchKeyring() ([]byte, error)
+}
 
-	// GetArchiveTypeForFile 根据具体文件确定归档类型
-	GetArchiveTypeForFile(filePath string) string
+// ReleaseRequestNormalizer 由版本列表只包含部分版本号的SDK实现，例如Java只列出特性版本
+type ReleaseRequestNormalizer interface {
+	// NormalizeReleaseRequest 返回在版本列表中查找时使用的版本请求，只用于查找，不影响已安装版本的名称
+	NormalizeReleaseRequest(version string) string
 }
 
 // VersionPrefixHandlers 定义了不同SDK的版本前缀处理逻辑
@@ -112,6 +140,7 @@ type BaseSDK struct {
 	Config          *config.Config
 	Provider        SDKProvider
 	VersionHandlers VersionPrefixHandlers
+	SkipSignature   bool // 跳过校验文件的签名验证，用于不发布签名的镜像
 }
 
 // NewBaseSDK 创建一个新的BaseSDK
@@ -120,26 +149,53 @@ func NewBaseSDK(name string, provider SDKProvider, handlers VersionPrefixHandler
 	if err != nil {
 		utils.Log.Warning(fmt.Sprintf("加载配置失败: %v，将使用默认配置", err))
 		cfg = &config.Config{
-			InstallDir:      filepath.Join(os.Getenv("HOME"), ".svm"),
-			CurrentVersions: make(map[string]string),
-			SDKs:            make(map[string]config.SDKConfig),
+			Version:    config.SchemaVersion,
+			InstallDir: config.GetDefaultInstallDir(),
+			SDKs:       make(map[string]config.SDKConfig),
 		}
 	}
 
+	// 应用配置的镜像地址，版本元数据缓存到缓存目录
+	mirrors := provider.GetMirrors()
+	mirrors.Configure(cfg.GetMirrors(name))
+	mirrors.Metadata.SetCache(&utils.MetadataCache{
+		Dir: cfg.GetMetadataCacheDir(name),
+		TTL: metadataTTL(cfg),
+	})
+
 	return &BaseSDK{
 		Name:            name,
-		InstallDir:      filepath.Join(cfg.InstallDir, name),
+		InstallDir:      cfg.GetSDKInstallDir(name),
 		Config:          cfg,
 		Provider:        provider,
 		VersionHandlers: handlers,
 	}
 }
 
+// metadataTTL 返回配置的版本元数据缓存有效期，未配置或无效时使用默认值
+func metadataTTL(cfg *config.Config) time.Duration {
+	if cfg.Cache.MetadataTTL == "" {
+		return utils.DefaultMetadataTTL
+	}
+
+	ttl, err := time.ParseDuration(cfg.Cache.MetadataTTL)
+	if err != nil || ttl < 0 {
+		utils.Log.Warning(fmt.Sprintf("无效的元数据缓存有效期 %q，使用默认值 %s", cfg.Cache.MetadataTTL, utils.DefaultMetadataTTL))
+		return utils.DefaultMetadataTTL
+	}
+	return ttl
+}
+
 // GetName 实现SDK接口
 func (b *BaseSDK) GetName() string {
 	return b.Name
 }
 
+// SetSkipSignature 设置是否跳过校验文件的签名验证
+func (b *BaseSDK) SetSkipSignature(skip bool) {
+	b.SkipSignature = skip
+}
+
 // GetCurrentVersion 获取当前使用的版本
 func (b *BaseSDK) GetCurrentVersion() (string, error) {
 	version := b.Config.GetCurrentVersion(b.GetName())
@@ -150,13 +206,13 @@ func (b *BaseSDK) GetCurrentVersion() (string, error) {
 }
 
 // List 统一实现的列表功能
-func (b *BaseSDK) List() ([]string, error) {
-	return b.Provider.GetVersionList()
+func (b *BaseSDK) List() ([]Release, error) {
+	return b.Provider.GetReleases()
 }
 
 // ListAll 统一实现的列出所有版本功能（不过滤）
-func (b *BaseSDK) ListAll() ([]string, error) {
-	return b.Provider.GetAllVersionList()
+func (b *BaseSDK) ListAll() ([]Release, error) {
+	return b.Provider.GetAllReleases()
 }
 
 // Install 统一实现的安装功能
@@ -169,25 +225,38 @@ func (b *BaseSDK) Install(version string) error {
 		return err
 	}
 
-	// 获取可用的版本列表
-	availableVersions, err := b.List()
+	// 获取所有可用的版本列表，部分版本和范围约束需要在完整列表中匹配
+	releases, err := b.ListAll()
 	if err != nil {
 		return fmt.Errorf("无法获取可用版本列表: %w", err)
 	}
 
-	utils.Log.Info(fmt.Sprintf("获取到 %d 个%s版本", len(availableVersions), b.Name))
+	utils.Log.Info(fmt.Sprintf("获取到 %d 个%s版本", len(releases), b.Name))
 
-	// 查找最佳版本
-	targetVersion, found := b.FindBestVersion(version, availableVersions, b.VersionHandlers)
-	if !found {
-		return fmt.Errorf("无法找到合适的%s版本，请检查网络连接或手动指定有效版本", b.Name)
+	// 查找满足约束的版本，下载失败时按从新到旧依次尝试
+	candidates, err := b.FindMatchingReleases(version, releases, b.VersionHandlers)
+	if err != nil {
+		return fmt.Errorf("无法找到合适的%s版本: %w", b.Name, err)
+	}
+
+	// 离线模式下只能使用已缓存的安装包，选择有缓存的最新匹配版本
+	if utils.IsOffline() {
+		candidates = b.filterReleases(candidates, b.hasCachedArchive)
+		if len(candidates) == 0 {
+			return fmt.Errorf("%w: 没有满足 %s 的%s安装包缓存", utils.ErrOffline, version, b.Name)
+		}
 	}
 
-	// 准备安装目录
-	versionDir, err := b.PrepareInstallDir(targetVersion)
+	targetRelease := candidates[0]
+	targetVersion := targetRelease.Version
+	utils.Log.Info(fmt.Sprintf("找到匹配的版本: %s", targetVersion))
+
+	// 同一版本的并行安装依次进行，避免同时下载同一个文件或移动同一个目录
+	unlock, err := b.lockVersion(targetVersion)
 	if err != nil {
 		return err
 	}
+	defer unlock()
 
 	// 检查是否有缓存文件
 	cachedFilePath, hasCachedFile := b.GetCachedFile(targetVersion)
@@ -199,40 +268,144 @@ func (b *BaseSDK) Install(version string) error {
 		osName := b.GetOSName()
 		arch := b.GetArchName()
 
-		// 获取下载URL
-		downloadUrl := b.Provider.GetDownloadURL(targetVersion, osName, arch)
-		if downloadUrl == "" {
-			return fmt.Errorf("无法为%s版本获取下载URL", targetVersion)
+		// 获取下载文件
+		file, err := b.Provider.GetDownloadURL(targetRelease, osName, arch)
+		if err != nil {
+			return fmt.Errorf("无法为%s版本获取下载URL: %w", targetVersion, err)
 		}
+		downloadUrl := file.URL
 
 		utils.Log.Info(fmt.Sprintf("下载URL: %s", downloadUrl))
 
 		// 下载或使用缓存
-		downloadedFile, err := b.DownloadOrUseCachedFile(downloadUrl, versionDir, targetVersion, "")
+		downloadedFile, err := b.DownloadOrUseCachedFile(file, targetVersion, "")
 		if err != nil {
+			// 校验值不匹配或签名无效说明文件被篡改或损坏，不再尝试其他版本
+			var checksumErr *utils.ChecksumError
+			var signatureErr *utils.SignatureError
+			if errors.As(err, &checksumErr) || errors.As(err, &signatureErr) {
+				return err
+			}
+
 			utils.Log.Error(fmt.Sprintf("下载失败: %v", err))
 			utils.Log.Info("尝试下一个版本...")
 			// 尝试回退到下一个版本
-			return b.FallthroughToNextVersion(targetVersion, availableVersions, b.Install, b.VersionHandlers)
+			return b.FallthroughToNextVersion(targetVersion, ReleaseVersions(candidates), b.Install, b.VersionHandlers)
 		}
 
 		archivePath = downloadedFile
 	}
 
-	// 获取归档类型并解压
-	utils.Log.Extract("正在解压文件...")
-	archiveType := b.Provider.GetArchiveType()
+	// 根据实际下载的文件确定归档类型
+	archiveType := utils.DetectArchiveType(archivePath)
+
+	return b.installArchive(targetVersion, archivePath, archiveType)
+}
+
+// ArchiveSource 表示用户指定的安装包来源，用于无法访问官方地址的环境或内部修改过的版本
+type ArchiveSource struct {
+	File   string // 本地安装包路径
+	URL    string // 自定义下载地址
+	SHA256 string // 期望的SHA-256校验值，为空时不校验
+}
+
+// InstallFromArchive 从指定的本地文件或地址安装，不查询版本列表也不使用官方下载地址，
+// 解压、目录整理、安装后处理和配置记录与 Install 相同
+func (b *BaseSDK) InstallFromArchive(version string, source ArchiveSource) error {
+	if (source.File == "") == (source.URL == "") {
+		return fmt.Errorf("需要指定本地安装包或下载地址中的一个")
+	}
 
-	// 如果archiveType是"auto"，则根据实际文件确定类型
-	if archiveType == "auto" {
-		archiveType = b.Provider.GetArchiveTypeForFile(archivePath)
+	// 规范化版本号，用户指定的版本名称会用作目录和锁文件的名称
+	version = b.VersionHandlers.Add(version)
+	if err := checkVersionName(version); err != nil {
+		return err
+	}
+
+	// 执行安装前的准备工作
+	if err := b.Provider.PreInstall(version); err != nil {
+		return err
+	}
+
+	unlock, err := b.lockVersion(version)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	var archivePath string
+	if source.File != "" {
+		absPath, err := filepath.Abs(source.File)
+		if err != nil {
+			return fmt.Errorf("获取绝对路径失败: %w", err)
+		}
+		if info, err := os.Stat(absPath); err != nil || info.IsDir() {
+			return fmt.Errorf("安装包不存在: %s", source.File)
+		}
+
+		if source.SHA256 != "" {
+			utils.Log.Check("正在校验 sha256 校验值...")
+			if err := utils.VerifyChecksum(absPath, "sha256", source.SHA256); err != nil {
+				return err
+			}
+			utils.Log.Success(fmt.Sprintf("sha256 校验通过: %s", source.SHA256))
+		} else {
+			utils.Log.Warning(fmt.Sprintf("未指定 %s 的校验值，跳过校验", filepath.Base(absPath)))
+		}
+		archivePath = absPath
+	} else {
+		// 自定义地址的安装包不是官方发布的文件，下载到临时目录，安装后删除，
+		// 不记录为该版本的缓存，之后 install <版本> 仍从官方地址下载
+		tempDir, err := os.MkdirTemp("", "svm-archive-")
+		if err != nil {
+			return fmt.Errorf("创建临时目录失败: %w", err)
+		}
+		defer os.RemoveAll(tempDir)
+
+		file := ReleaseFile{URL: source.URL}
+		if source.SHA256 != "" {
+			file.Checksum, file.ChecksumAlgorithm = source.SHA256, "sha256"
+		}
+		archivePath = filepath.Join(tempDir, archiveFileName(source.URL))
+		if err := b.downloadVerified(file, archivePath, ""); err != nil {
+			return err
+		}
+		if source.SHA256 == "" {
+			utils.Log.Warning(fmt.Sprintf("未指定 %s 的校验值，跳过校验", filepath.Base(archivePath)))
+		}
 	}
 
+	// 用户指定的文件根据文件名或文件头确定归档类型
+	archiveType := utils.DetectArchiveType(archivePath)
+	return b.installArchive(version, archivePath, archiveType)
+}
+
+// checkVersionName 检查用户指定的版本名称，名称不能包含路径分隔符或 ..，
+// 也不能是 current 或以 . 开头（SDK安装目录中的 current 链接、.locks 和临时目录）
+func checkVersionName(version string) error {
+	if version == "" || version == "current" || strings.HasPrefix(version, ".") ||
+		strings.Contains(version, "..") || strings.ContainsAny(version, `/\:`) {
+		return fmt.Errorf("无效的版本名称: %q", version)
+	}
+	return nil
+}
+
+// installArchive 将安装包解压到临时目录，整理目录结构并执行安装后的处理，
+// 全部成功后才将版本目录重命名到位并记录到配置，失败时删除临时目录，不留下不完整的安装
+func (b *BaseSDK) installArchive(targetVersion, archivePath, archiveType string) error {
+	stagingRoot, versionDir, release, err := b.createStagingDir(targetVersion)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	utils.Log.Extract("正在解压文件...")
 	utils.Log.Info(fmt.Sprintf("归档类型: %s, 文件路径: %s", archiveType, archivePath))
 
 	var err2 error
 
-	if archiveType == "zip" {
+	switch archiveType {
+	case utils.ArchiveZip:
 		utils.Log.Extract(fmt.Sprintf("开始解压zip文件: %s 到 %s", archivePath, versionDir))
 		err2 = utils.ExtractZip(archivePath, versionDir)
 		if err2 != nil {
@@ -240,7 +413,7 @@ func (b *BaseSDK) Install(version string) error {
 		} else {
 			utils.Log.Info("解压zip文件成功")
 		}
-	} else if archiveType == "tar.gz" || archiveType == "tgz" {
+	case utils.ArchiveTarGz:
 		utils.Log.Extract(fmt.Sprintf("开始解压tar.gz文件: %s 到 %s", archivePath, versionDir))
 		err2 = utils.ExtractTarGzFile(archivePath, versionDir)
 		if err2 != nil {
@@ -248,16 +421,15 @@ func (b *BaseSDK) Install(version string) error {
 		} else {
 			utils.Log.Info("解压tar.gz文件成功")
 		}
-	} else if archiveType == "exe" {
-		// 对于.exe文件，使用ExtractExe函数处理
-		utils.Log.Extract(fmt.Sprintf("开始处理exe文件: %s", archivePath))
-		err2 = utils.ExtractExe(archivePath, versionDir)
+	case utils.ArchiveTarXz:
+		utils.Log.Extract(fmt.Sprintf("开始解压tar.xz文件: %s 到 %s", archivePath, versionDir))
+		err2 = utils.ExtractTarXzFile(archivePath, versionDir)
 		if err2 != nil {
-			utils.Log.Error(fmt.Sprintf("处理exe文件失败: %v", err2))
+			utils.Log.Error(fmt.Sprintf("解压tar.xz文件失败: %v", err2))
 		} else {
-			utils.Log.Info("处理exe文件成功")
+			utils.Log.Info("解压tar.xz文件成功")
 		}
-	} else if archiveType == "none" {
+	case utils.ArchiveNone:
 		// 对于不需要解压的类型（如可执行安装程序），直接复制到目标目录
 		utils.Log.Info("无需解压，直接处理...")
 
@@ -272,11 +444,12 @@ func (b *BaseSDK) Install(version string) error {
 				utils.Log.Info("复制文件成功")
 			}
 		}
-	} else {
-		err2 = fmt.Errorf("不支持的归档类型: %s", archiveType)
+	default:
+		err2 = fmt.Errorf("无法识别 %s 的归档类型，支持 zip、tar.gz、tar.xz", filepath.Base(archivePath))
 	}
 
 	if err2 != nil {
+		b.discardUnsafeArchive(targetVersion, archivePath, err2)
 		return fmt.Errorf("解压失败: %w", err2)
 	}
 
@@ -325,19 +498,55 @@ func (b *BaseSDK) Install(version string) error {
 		}
 	}
 
+	// 需要把安装目录写入文件的SDK（例如编译时的 --prefix）使用最终的目录
+	finalDir := filepath.Join(b.InstallDir, b.versionPath(targetVersion))
+	if target, ok := b.Provider.(interface{ SetInstallTarget(string) }); ok {
+		target.SetInstallTarget(finalDir)
+	}
+
 	// 执行安装后的处理
 	if err := b.Provider.PostInstall(targetVersion, versionDir); err != nil {
 		return err
 	}
 
+	// 移动到安装目录并记录
+	finalDir, err = b.commitStaging(targetVersion, stagingRoot)
+	if err != nil {
+		return err
+	}
+	if err := b.recordInstallDir(targetVersion, finalDir); err != nil {
+		return err
+	}
+
 	utils.Log.Info(fmt.Sprintf("%s %s 安装完成", b.Name, targetVersion))
 	return nil
 }
 
+// discardUnsafeArchive 归档不安全时删除缓存的安装包，避免再次使用
+func (b *BaseSDK) discardUnsafeArchive(version, archivePath string, cause error) {
+	var unsafeErr *utils.UnsafeArchiveError
+	if !errors.As(cause, &unsafeErr) {
+		return
+	}
+
+	// 只删除缓存目录中的安装包，用户通过 --from-file 指定的文件保持不变
+	cacheDir := filepath.Join(b.Config.GetCacheDir(), b.GetName())
+	if filepath.Dir(archivePath) != cacheDir {
+		return
+	}
+	utils.Log.Delete(fmt.Sprintf("删除不安全的安装包: %s", archivePath))
+	if err := os.Remove(archivePath); err != nil && !os.IsNotExist(err) {
+		utils.Log.Warning(fmt.Sprintf("删除安装包失败: %v", err))
+	}
+	if err := b.Config.ClearCacheFile(b.GetName(), version); err != nil {
+		utils.Log.Warning(fmt.Sprintf("保存配置失败: %v", err))
+	}
+}
+
 // Remove 统一实现的移除功能
 func (b *BaseSDK) Remove(version string) error {
 	// 规范化版本号
-	version = b.VersionHandlers.Add(version)
+	version = b.normalizeVersion(version)
 
 	// 获取版本信息
 	versionInfo, exists := b.Config.GetVersionInfo(b.GetName(), version)
@@ -362,6 +571,15 @@ func (b *BaseSDK) Remove(version string) error {
 		}
 	}
 
+	// 登记的外部目录只取消登记，不删除其中的文件
+	if versionInfo.Linked {
+		if err := b.Config.RemoveVersionInfo(b.GetName(), version); err != nil {
+			return fmt.Errorf("更新配置失败: %w", err)
+		}
+		utils.Log.Success(fmt.Sprintf("已取消登记 %s %s，目录 %s 未删除", b.GetName(), version, versionInfo.InstallDir))
+		return nil
+	}
+
 	// 删除安装目录
 	if err := os.RemoveAll(versionInfo.InstallDir); err != nil {
 		return fmt.Errorf("删除目录失败: %w", err)
@@ -380,16 +598,12 @@ func (b *BaseSDK) Remove(version string) error {
 // Use 统一实现的切换版本功能
 func (b *BaseSDK) Use(version string) error {
 	// 规范化版本号
-	version = b.VersionHandlers.Add(version)
+	version = b.normalizeVersion(version)
 
-	// 首先尝试直接使用版本号作为子目录（适用于大多数SDK）
-	versionDir := filepath.Join(b.InstallDir, version)
-	exists, _ := utils.CheckDirExists(versionDir)
-
-	// 如果不存在，尝试.NET SDK的目录结构
-	if !exists {
-		versionDir = filepath.Join(b.InstallDir, "sdk", version)
-		exists, _ = utils.CheckDirExists(versionDir)
+	// 查找已安装的版本目录（包括登记的外部目录）
+	versionDir, exists := b.findVersionDir(version)
+	if dir, linked := b.linkedDir(version); linked && !exists {
+		return fmt.Errorf("登记的目录 %s 不存在，可以使用 remove 取消登记", dir)
 	}
 
 	if !exists {
@@ -401,15 +615,7 @@ func (b *BaseSDK) Use(version string) error {
 
 		// 更新版本和版本目录
 		version = fullVersion
-
-		// 再次尝试两种目录结构
-		versionDir = filepath.Join(b.InstallDir, version)
-		exists, _ = utils.CheckDirExists(versionDir)
-
-		if !exists {
-			versionDir = filepath.Join(b.InstallDir, "sdk", version)
-			exists, _ = utils.CheckDirExists(versionDir)
-		}
+		versionDir, exists = b.findVersionDir(version)
 
 		// 再次检查版本是否已安装
 		if !exists {
@@ -419,22 +625,18 @@ func (b *BaseSDK) Use(version string) error {
 			}
 
 			// 安装后再次检查目录
-			versionDir = filepath.Join(b.InstallDir, version)
-			exists, _ = utils.CheckDirExists(versionDir)
-
+			versionDir, exists = b.findVersionDir(version)
 			if !exists {
-				versionDir = filepath.Join(b.InstallDir, "sdk", version)
-				exists, _ = utils.CheckDirExists(versionDir)
-
-				if !exists {
-					return fmt.Errorf("安装后版本目录仍不存在")
-				}
+				return fmt.Errorf("安装后版本目录仍不存在")
 			}
 		}
 	}
 
-	// 创建或更新软链接
+	// 创建或更新软链接，只使用登记的外部目录时安装目录可能还不存在
 	currentDir := filepath.Join(b.InstallDir, "current")
+	if err := os.MkdirAll(b.InstallDir, 0755); err != nil {
+		return fmt.Errorf("创建安装目录失败: %w", err)
+	}
 
 	// 删除旧的current目录或符号链接
 	if fileInfo, err := os.Lstat(currentDir); err == nil {
@@ -474,10 +676,12 @@ func (b *BaseSDK) Use(version string) error {
 		}
 	}
 
-	// 创建一个文件来记录当前版本
-	versionFile := filepath.Join(currentDir, ".version")
-	if err := os.WriteFile(versionFile, []byte(version), 0644); err != nil {
-		utils.Log.Warning(fmt.Sprintf("警告：写入版本文件失败: %v", err))
+	// 创建一个文件来记录当前版本，不向登记的外部目录写入文件
+	if _, linked := b.linkedDir(version); !linked {
+		versionFile := filepath.Join(currentDir, ".version")
+		if err := os.WriteFile(versionFile, []byte(version), 0644); err != nil {
+			utils.Log.Warning(fmt.Sprintf("警告：写入版本文件失败: %v", err))
+		}
 	}
 
 	// 确保current目录存在
@@ -514,18 +718,9 @@ func (b *BaseSDK) SetupEnv(version string) error {
 	// 使用固定的current目录而不是版本目录
 	currentDir := filepath.Join(b.InstallDir, "current")
 
-	// 首先尝试.NET SDK的目录结构
-	versionDir := filepath.Join(b.InstallDir, "sdk", version)
-
 	// 检查版本目录是否存在
-	exists, _ := utils.CheckDirExists(versionDir)
-	if !exists {
-		// 如果不存在，尝试直接使用版本号作为子目录（适用于其他SDK）
-		versionDir = filepath.Join(b.InstallDir, version)
-		exists, _ = utils.CheckDirExists(versionDir)
-		if !exists {
-			return fmt.Errorf("版本目录不存在: %s", versionDir)
-		}
+	if _, exists := b.findVersionDir(version); !exists {
+		return fmt.Errorf("版本 %s 的目录不存在", version)
 	}
 
 	// 确保current目录存在
@@ -590,38 +785,94 @@ func (b *BaseSDK) SetupEnv(version string) error {
 	return nil
 }
 
-// FindBestVersion 查找最佳匹配的版本
-// 如果请求的版本不存在，则尝试找到最接近的较低版本
-func (b *BaseSDK) FindBestVersion(requestedVersion string, availableVersions []string, handlers VersionPrefixHandlers) (string, bool) {
-	// 标准化版本号格式
-	originalVersion := requestedVersion
-	requestedVersion = handlers.Add(requestedVersion)
+// FindBestVersion 查找满足版本约束的最高版本
+// 请求可以是精确版本、部分版本（如 18、1.22）、范围约束（如 ^18.2、>=1.21 <1.23），
+// 也可以是 lts/* 或 lts/<代号>（如Node.js的 lts/iron）
+func (b *BaseSDK) FindBestVersion(requestedVersion string, releases []Release, handlers VersionPrefixHandlers) (string, error) {
+	candidates, err := b.FindMatchingReleases(requestedVersion, releases, handlers)
+	if err != nil {
+		return "", err
+	}
 
-	// 确保版本列表已经按降序排序（从新到旧）
-	utils.SortVersionsDesc(availableVersions)
+	targetVersion := candidates[0].Version
+	if handlers.Remove(targetVersion) != handlers.Remove(requestedVersion) {
+		utils.Log.Info(fmt.Sprintf("请求的版本 %s 解析为 %s", requestedVersion, targetVersion))
+	} else {
+		utils.Log.Info(fmt.Sprintf("找到匹配的版本: %s", targetVersion))
+	}
+
+	return targetVersion, nil
+}
+
+// FindMatchingReleases 返回满足版本约束的所有版本（从新到旧）
+func (b *BaseSDK) FindMatchingReleases(requestedVersion string, releases []Release, handlers VersionPrefixHandlers) ([]Release, error) {
+	// 去掉版本前缀（如Node.js的v），约束解析会自行处理
+	constraint := handlers.Remove(strings.TrimSpace(requestedVersion))
+	if normalizer, ok := b.Provider.(ReleaseRequestNormalizer); ok {
+		constraint = normalizer.NormalizeReleaseRequest(constraint)
+	}
+
+	// lts/* 和 lts/<代号> 先筛选出对应的LTS版本，再在其中选择最新版本
+	if codename, ok := strings.CutPrefix(strings.ToLower(constraint), "lts/"); ok {
+		var ltsReleases []Release
+		for _, release := range releases {
+			if release.IsLTS() && (codename == "*" || strings.EqualFold(release.LTS, codename)) {
+				ltsReleases = append(ltsReleases, release)
+			}
+		}
+		if len(ltsReleases) == 0 {
+			return nil, fmt.Errorf("版本别名 %s 没有对应的%s版本", constraint, b.Name)
+		}
+		releases = ltsReleases
+		constraint = "*"
+	}
 
 	// 调试输出
-	if len(availableVersions) > 0 {
-		count := min(5, len(availableVersions))
-		utils.Log.Info(fmt.Sprintf("最新的几个%s版本: %v", b.Name, availableVersions[:count]))
+	versions := utils.GetSortedVersions(ReleaseVersions(releases), "")
+	if len(versions) > 0 {
+		count := min(5, len(versions))
+		utils.Log.Info(fmt.Sprintf("最新的几个%s版本: %v", b.Name, versions[:count]))
 	}
 
-	// 使用utils包中的函数查找最佳匹配版本
-	// 构造stripPrefix参数 - 对于Node.js是"v"，对于其他SDK是""
-	stripPrefix := ""
-	if handlers.Has(requestedVersion) {
-		stripPrefix = requestedVersion[:1] // 获取前缀的第一个字符
+	matches, err := utils.MatchVersions(constraint, versions)
+	if err != nil {
+		return nil, err
 	}
 
-	targetVersion, found := utils.FindBestMatchingVersion(requestedVersion, availableVersions, stripPrefix)
+	byVersion := make(map[string]Release, len(releases))
+	for _, release := range releases {
+		byVersion[release.Version] = release
+	}
 
-	if found && targetVersion != requestedVersion {
-		utils.Log.Info(fmt.Sprintf("请求的版本 %s 不可用，将使用 %s", originalVersion, targetVersion))
-	} else if found {
-		utils.Log.Info(fmt.Sprintf("找到匹配的版本: %s", targetVersion))
+	result := make([]Release, 0, len(matches))
+	for _, version := range matches {
+		result = append(result, byVersion[version])
 	}
+	return result, nil
+}
+
+// ResolveVersionFile 在指定目录中查找生态原生的版本文件并解析出版本请求
+func (b *BaseSDK) ResolveVersionFile(dir string) (string, string, error) {
+	for _, name := range b.Provider.GetVersionFiles() {
+		filePath := filepath.Join(dir, name)
+		if info, err := os.Stat(filePath); err != nil || info.IsDir() {
+			continue
+		}
+
+		version, err := b.Provider.ParseVersionFile(filePath)
+		if err != nil {
+			return "", filePath, fmt.Errorf("解析版本文件 %s 失败: %w", filePath, err)
+		}
+
+		// 文件中没有可用的版本信息（例如go.mod缺少go指令），继续检查下一个文件
+		if version == "" {
+			continue
+		}
 
-	return targetVersion, found
+		return version, filePath, nil
+	}
+
+	return "", "", nil
 }
 
 // ValidateDownloadURL 验证下载URL是否有效
@@ -640,53 +891,14 @@ func (b *BaseSDK) ValidateDownloadURL(url string) (bool, error) {
 	return exists, err
 }
 
-// PrepareInstallDir 准备安装目录，优先检查是否已有安装目录
-func (b *BaseSDK) PrepareInstallDir(version string) (string, error) {
-	// 检查配置中是否有版本信息
-	versionInfo, exists := b.Config.GetVersionInfo(b.GetName(), version)
-
-	// 已有安装目录，直接返回
-	if exists && versionInfo.InstallDir != "" {
-		// 验证目录是否存在
-		if _, err := os.Stat(versionInfo.InstallDir); err == nil {
-			utils.Log.Info(fmt.Sprintf("发现已有安装目录: %s", versionInfo.InstallDir))
-			return versionInfo.InstallDir, nil
-		}
-	}
-
-	// 创建安装目录
-	versionDir := filepath.Join(b.InstallDir, version)
-	if err := os.MkdirAll(versionDir, 0755); err != nil {
-		return "", fmt.Errorf("创建安装目录失败: %w", err)
-	}
-
-	// 清理可能存在的旧文件
-	entries, err := os.ReadDir(versionDir)
-	if err == nil && len(entries) > 0 {
-		utils.Log.Info("清理安装目录中的旧文件...")
-		for _, entry := range entries {
-			path := filepath.Join(versionDir, entry.Name())
-			if err := os.RemoveAll(path); err != nil {
-				utils.Log.Warning(fmt.Sprintf("无法删除文件 %s: %v", path, err))
-			}
-		}
-	}
-
-	// 保存安装目录信息到配置
-	if !exists {
-		// 如果不存在版本信息，创建新的，保留可能存在的缓存文件路径
-		versionInfo = config.SDKVersionInfo{InstallDir: versionDir}
-	} else {
-		// 更新安装目录，保留缓存文件路径
-		versionInfo.InstallDir = versionDir
-	}
-
-	// 保存到配置
+// recordInstallDir 在配置中记录版本的安装目录，保留已有的缓存文件信息
+func (b *BaseSDK) recordInstallDir(version, installDir string) error {
+	versionInfo, _ := b.Config.GetVersionInfo(b.GetName(), version)
+	versionInfo.InstallDir = installDir
 	if err := b.Config.SetVersionInfo(b.GetName(), version, versionInfo); err != nil {
-		utils.Log.Warning(fmt.Sprintf("保存版本信息失败: %v", err))
+		return fmt.Errorf("保存版本信息失败: %w", err)
 	}
-
-	return versionDir, nil
+	return nil
 }
 
 // GetArchName 获取当前架构名称
@@ -757,6 +969,9 @@ func (b *BaseSDK) GetCachedFile(version string) (string, bool) {
 	// 检查缓存文件是否存在
 	if _, err := os.Stat(versionInfo.CacheFilePath); err != nil {
 		utils.Log.Warning(fmt.Sprintf("缓存文件不存在: %s", versionInfo.CacheFilePath))
+		if err := b.Config.ClearCacheFile(b.GetName(), version); err != nil {
+			utils.Log.Warning(fmt.Sprintf("更新版本信息失败: %v", err))
+		}
 		return "", false
 	}
 
@@ -766,12 +981,21 @@ func (b *BaseSDK) GetCachedFile(version string) (string, bool) {
 		return "", false
 	}
 
+	// 使用下载时记录的校验值检查缓存文件是否完整
+	if versionInfo.Checksum != "" {
+		if err := utils.VerifyChecksum(versionInfo.CacheFilePath, versionInfo.ChecksumAlgorithm, versionInfo.Checksum); err != nil {
+			utils.Log.Warning(fmt.Sprintf("缓存文件校验失败，将重新下载: %v", err))
+			b.CleanupTempFile(versionInfo.CacheFilePath)
+			return "", false
+		}
+	}
+
 	utils.Log.Info(fmt.Sprintf("使用缓存文件: %s", versionInfo.CacheFilePath))
 	return versionInfo.CacheFilePath, true
 }
 
-// SaveCacheFile 保存缓存文件信息
-func (b *BaseSDK) SaveCacheFile(version, filePath string) error {
+// SaveCacheFile 保存缓存文件信息及已校验的校验值
+func (b *BaseSDK) SaveCacheFile(version, filePath, algorithm, checksum string) error {
 	// 获取版本信息
 	versionInfo, exists := b.Config.GetVersionInfo(b.GetName(), version)
 	if !exists {
@@ -780,6 +1004,8 @@ func (b *BaseSDK) SaveCacheFile(version, filePath string) error {
 
 	// 更新缓存文件路径
 	versionInfo.CacheFilePath = filePath
+	versionInfo.Checksum = checksum
+	versionInfo.ChecksumAlgorithm = algorithm
 	utils.Log.Info(fmt.Sprintf("保存缓存文件信息: %s -> %s", version, filePath))
 
 	// 保存版本信息
@@ -787,15 +1013,25 @@ func (b *BaseSDK) SaveCacheFile(version, filePath string) error {
 }
 
 // DownloadOrUseCachedFile 下载文件或使用缓存文件
-func (b *BaseSDK) DownloadOrUseCachedFile(url string, targetDir string, version string, tip string) (string, error) {
+// 文件先下载到 .part 文件并支持断点续传，使用发布方提供的校验值校验通过后才放入缓存，
+// 不一致时删除文件并返回 *utils.ChecksumError
+func (b *BaseSDK) DownloadOrUseCachedFile(file ReleaseFile, version string, tip string) (string, error) {
 	// 检查是否有缓存文件
 	cachedFilePath, hasCachedFile := b.GetCachedFile(version)
 	if hasCachedFile {
 		return cachedFilePath, nil
 	}
 
-	// 没有缓存文件，下载新文件
-	fileName := filepath.Base(url)
+	return b.downloadArchive(file, version, tip)
+}
+
+// downloadArchive 下载文件到缓存目录，校验通过后记录为指定版本的缓存文件
+func (b *BaseSDK) downloadArchive(file ReleaseFile, version string, tip string) (string, error) {
+	// 获取期望的校验值
+	file, err := b.resolveChecksum(file)
+	if err != nil {
+		return "", err
+	}
 
 	// 创建缓存目录
 	cacheDir := filepath.Join(b.Config.GetCacheDir(), b.GetName())
@@ -804,54 +1040,274 @@ func (b *BaseSDK) DownloadOrUseCachedFile(url string, targetDir string, version
 	}
 
 	// 缓存文件路径
+	fileName := archiveFileName(file.URL)
 	filePath := filepath.Join(cacheDir, fileName)
 
-	utils.Log.Download(fmt.Sprintf("下载文件: %s", url))
-	utils.Log.Info(fmt.Sprintf("缓存路径: %s", filePath))
+	if err := b.downloadVerified(file, filePath, tip); err != nil {
+		return "", err
+	}
+	if file.Checksum == "" {
+		utils.Log.Warning(fmt.Sprintf("发布方未提供 %s 的校验值，跳过校验", fileName))
+	}
+
+	// 保存缓存文件信息
+	if err := b.SaveCacheFile(version, filePath, file.ChecksumAlgorithm, file.Checksum); err != nil {
+		utils.Log.Warning(fmt.Sprintf("保存缓存文件信息失败: %v", err))
+	}
+
+	return filePath, nil
+}
+
+// downloadVerified 下载文件到指定路径，指定了校验值时校验通过后才放到该路径
+func (b *BaseSDK) downloadVerified(file ReleaseFile, filePath string, tip string) error {
+	utils.Log.Download(fmt.Sprintf("下载文件: %s", file.URL))
+	utils.Log.Info(fmt.Sprintf("保存路径: %s", filePath))
 
 	if tip != "" {
 		utils.Log.Info(tip)
 	}
 
-	if err := utils.DownloadFile(url, filePath); err != nil {
-		return "", fmt.Errorf("下载失败: %w", err)
+	// 先下载到 .part 文件，中断后重试安装时从断点继续
+	partPath := utils.PartFilePath(filePath)
+	if err := b.downloadFromMirrors(file.URL, partPath); err != nil {
+		return fmt.Errorf("下载失败: %w", err)
 	}
 
-	// 保存缓存文件信息
-	if err := b.SaveCacheFile(version, filePath); err != nil {
-		utils.Log.Warning(fmt.Sprintf("保存缓存文件信息失败: %v", err))
+	// 校验下载的文件，校验失败的文件不能用于续传
+	if file.Checksum != "" {
+		utils.Log.Check(fmt.Sprintf("正在校验 %s 校验值...", file.ChecksumAlgorithm))
+		if err := utils.VerifyChecksum(partPath, file.ChecksumAlgorithm, file.Checksum); err != nil {
+			utils.RemovePartFile(partPath)
+			return err
+		}
+		utils.Log.Success(fmt.Sprintf("%s 校验通过: %s", file.ChecksumAlgorithm, file.Checksum))
 	}
 
-	return filePath, nil
+	// 下载完成且校验通过后才放到目标路径
+	return utils.PromotePartFile(partPath, filePath)
+}
+
+// archiveFileName 返回下载地址中的文件名，忽略查询参数
+func archiveFileName(rawURL string) string {
+	if u, err := url.Parse(rawURL); err == nil && u.Path != "" {
+		return path.Base(u.Path)
+	}
+	return filepath.Base(rawURL)
+}
+
+// downloadFromMirrors 依次从配置的镜像下载文件，一个镜像失败时尝试下一个
+func (b *BaseSDK) downloadFromMirrors(url, partPath string) error {
+	urls := b.Provider.GetMirrors().Archives.URLs(url)
+
+	var lastErr error
+	for i, mirrorURL := range urls {
+		if mirrorURL != url {
+			utils.Log.Download(fmt.Sprintf("从镜像下载: %s", mirrorURL))
+		}
+
+		err := utils.ResumeDownload(mirrorURL, partPath)
+		if err == nil {
+			return nil
+		}
+
+		lastErr = err
+		if i < len(urls)-1 {
+			utils.Log.Warning(fmt.Sprintf("从 %s 下载失败: %v，尝试下一个镜像", mirrorURL, err))
+		}
+	}
+	return lastErr
+}
+
+// resolveChecksum 补全下载文件的期望校验值，必要时从校验文件中查找
+func (b *BaseSDK) resolveChecksum(file ReleaseFile) (ReleaseFile, error) {
+	if file.Checksum != "" || file.ChecksumURL == "" {
+		if file.Checksum != "" && file.ChecksumAlgorithm == "" {
+			file.ChecksumAlgorithm = "sha256"
+		}
+		return file, nil
+	}
+
+	utils.Log.Download(fmt.Sprintf("获取校验文件: %s", file.ChecksumURL))
+	data, err := b.Provider.GetMirrors().Archives.Fetch(file.ChecksumURL)
+	if err != nil {
+		return file, fmt.Errorf("获取校验文件失败: %w", err)
+	}
+
+	// 校验文件本身需要通过发布方的签名验证，否则其中的校验值不可信
+	if len(file.SignatureURLs) > 0 {
+		if b.SkipSignature {
+			utils.Log.Warning(fmt.Sprintf("已跳过 %s 的签名验证", file.ChecksumURL))
+		} else {
+			data, err = b.verifyChecksumSignature(file, data)
+			if err != nil {
+				return file, err
+			}
+		}
+	}
+
+	checksum, ok := utils.ParseChecksumFile(data, file.Name)
+	if !ok {
+		return file, fmt.Errorf("校验文件 %s 中没有 %s 的校验值", file.ChecksumURL, file.Name)
+	}
+
+	file.Checksum = checksum
+	if file.ChecksumAlgorithm == "" {
+		file.ChecksumAlgorithm = "sha256"
+	}
+	return file, nil
+}
+
+// verifyChecksumSignature 使用公钥环验证校验文件的签名，返回可信的校验文件内容
+// 依次尝试 SignatureURLs：分离签名验证已下载的校验文件，明文签名则使用其中签名的原文。
+// 一个签名无法获取或验证失败时尝试下一个，全部失败时才返回错误
+func (b *BaseSDK) verifyChecksumSignature(file ReleaseFile, data []byte) ([]byte, error) {
+	keyring, err := b.loadKeyring()
+	if err != nil {
+		return nil, &utils.SignatureError{URL: file.ChecksumURL, Reason: "无法加载公钥环", Err: err}
+	}
+
+	var fetchErr, verifyErr error
+	var invalidURL string
+	for _, signatureURL := range file.SignatureURLs {
+		utils.Log.Download(fmt.Sprintf("获取签名文件: %s", signatureURL))
+		signature, err := b.Provider.GetMirrors().Archives.Fetch(signatureURL)
+		if err != nil {
+			utils.Log.Warning(fmt.Sprintf("获取签名文件失败: %v", err))
+			fetchErr = err
+			continue
+		}
+
+		utils.Log.Check("正在验证校验文件签名...")
+		signed, signer, err := verifySignature(keyring, data, signature)
+		if err != nil {
+			utils.Log.Warning(fmt.Sprintf("签名 %s 验证失败: %v", signatureURL, err))
+			verifyErr, invalidURL = err, signatureURL
+			continue
+		}
+		utils.Log.Success(fmt.Sprintf("签名验证通过，签名者: %s", utils.SignerName(signer)))
+		return signed, nil
+	}
+
+	if verifyErr != nil {
+		return nil, &utils.SignatureError{URL: invalidURL, Reason: "签名无效或签名者不在公钥环中", Err: verifyErr}
+	}
+	return nil, &utils.SignatureError{
+		URL:    file.ChecksumURL,
+		Reason: "未找到签名文件，如果使用的镜像不发布签名，请使用 --skip-signature 跳过验证",
+		Err:    fetchErr,
+	}
 }
 
-// getLatestMatchingVersion 获取最新匹配的版本
+// verifySignature 验证一个签名文件，返回签名的内容和签名者
+// 明文签名返回其中签名的原文，分离签名返回被验证的 data
+func verifySignature(keyring openpgp.EntityList, data, signature []byte) ([]byte, *openpgp.Entity, error) {
+	if strings.Contains(string(signature), "-----BEGIN PGP SIGNED MESSAGE-----") {
+		return utils.VerifyClearsigned(keyring, signature)
+	}
+
+	signer, err := utils.VerifyDetachedSignature(keyring, data, signature)
+	if err != nil {
+		return nil, nil, err
+	}
+	return data, signer, nil
+}
+
+// loadKeyring 加载SDK的公钥环
+// 配置的公钥环或默认路径的公钥环（svm config refresh-keyring 保存）存在时使用该文件，否则使用内置的发布方公钥
+func (b *BaseSDK) loadKeyring() (openpgp.EntityList, error) {
+	keyringPath := b.Config.GetKeyringPath(b.GetName())
+	if _, err := os.Stat(keyringPath); err == nil {
+		return utils.LoadKeyring(keyringPath)
+	} else if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("检查公钥环失败: %w", err)
+	}
+
+	// 用户指定的公钥环必须存在
+	if keyringPath != config.GetDefaultKeyringPath(b.GetName()) {
+		return nil, fmt.Errorf("公钥环不存在: %s", keyringPath)
+	}
+
+	provider, ok := b.Provider.(KeyringProvider)
+	if !ok {
+		return nil, fmt.Errorf("公钥环不存在: %s，请使用 svm config set-keyring %s <文件> 指定", keyringPath, b.GetName())
+	}
+
+	keyring, err := utils.ParseKeyring(provider.DefaultKeyring())
+	if err != nil || len(keyring) == 0 {
+		return nil, fmt.Errorf("内置的%s发布方公钥无效，请使用 svm config refresh-keyring %s 获取或 svm config set-keyring %s <文件> 指定", b.Name, b.GetName(), b.GetName())
+	}
+	return keyring, nil
+}
+
+// RefreshKeyring 从发布方获取当前的公钥并保存到默认公钥环路径，之后的验证使用保存的公钥代替内置的公钥。
+// 返回保存的公钥数量和路径
+func (b *BaseSDK) RefreshKeyring() (int, string, error) {
+	provider, ok := b.Provider.(KeyringProvider)
+	if !ok {
+		return 0, "", fmt.Errorf("%s 不使用签名验证", b.Name)
+	}
+
+	data, err := provider.FetchKeyring()
+	if err != nil {
+		return 0, "", fmt.Errorf("获取发布方公钥失败: %w", err)
+	}
+	keyring, err := utils.ParseKeyring(data)
+	if err != nil {
+		return 0, "", fmt.Errorf("解析发布方公钥失败: %w", err)
+	}
+
+	keyringPath := config.GetDefaultKeyringPath(b.GetName())
+	if err := os.MkdirAll(filepath.Dir(keyringPath), 0755); err != nil {
+		return 0, "", fmt.Errorf("创建公钥目录失败: %w", err)
+	}
+	if err := os.WriteFile(keyringPath, data, 0644); err != nil {
+		return 0, "", fmt.Errorf("保存公钥环失败: %w", err)
+	}
+	return len(keyring), keyringPath, nil
+}
+
+// getLatestMatchingVersion 获取满足版本约束的最新版本
 func (b *BaseSDK) getLatestMatchingVersion(versionPrefix string) (string, error) {
-	versions, err := b.List()
+	releases, err := b.ListAll()
 	if err != nil {
 		return "", err
 	}
 
-	// 处理空版本，返回最新版本
-	if versionPrefix == "" || versionPrefix == b.VersionHandlers.Add("") {
-		if len(versions) > 0 {
-			return versions[0], nil
+	// 离线模式下优先选择已安装或已缓存安装包的版本
+	if utils.IsOffline() {
+		local := b.filterReleases(releases, func(version string) bool {
+			return b.isInstalled(version) || b.hasCachedArchive(version)
+		})
+		if version, err := b.FindBestVersion(versionPrefix, local, b.VersionHandlers); err == nil {
+			return version, nil
 		}
-		return "", fmt.Errorf("没有可用的%s版本", b.Name)
 	}
 
-	// 寻找匹配的版本
-	for _, v := range versions {
-		if strings.HasPrefix(v, versionPrefix) {
-			return v, nil
+	return b.FindBestVersion(versionPrefix, releases, b.VersionHandlers)
+}
+
+// filterReleases 返回版本号满足条件的版本
+func (b *BaseSDK) filterReleases(releases []Release, keep func(version string) bool) []Release {
+	var filtered []Release
+	for _, release := range releases {
+		if keep(release.Version) {
+			filtered = append(filtered, release)
 		}
 	}
+	return filtered
+}
 
-	// 尝试寻找近似匹配
-	targetVersion, found := b.FindBestVersion(versionPrefix, versions, b.VersionHandlers)
-	if found {
-		return targetVersion, nil
+// hasCachedArchive 判断指定版本的安装包是否已缓存
+func (b *BaseSDK) hasCachedArchive(version string) bool {
+	versionInfo, exists := b.Config.GetVersionInfo(b.GetName(), version)
+	if !exists || versionInfo.CacheFilePath == "" {
+		return false
 	}
+	_, err := os.Stat(versionInfo.CacheFilePath)
+	return err == nil
+}
 
-	return "", fmt.Errorf("没有找到匹配的%s版本: %s", b.Name, versionPrefix)
+// isInstalled 判断指定版本是否已安装
+func (b *BaseSDK) isInstalled(version string) bool {
+	return isVersionInstalled(b.Config, b.GetName(), version)
 }
@@ -0,0 +1,205 @@
+package sdk
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"svm/internal/config"
+	"svm/internal/utils"
+	"testing"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/ProtonMail/go-crypto/openpgp/clearsign"
+)
+
+func TestFindMatchingReleases(t *testing.T) {
+	releases := []Release{
+		{Version: "v22.2.0"},
+		{Version: "v22.0.0-rc.1"},
+		{Version: "v20.14.0", LTS: "Iron"},
+		{Version: "v20.9.0", LTS: "Iron"},
+		{Version: "v20.8.0"},
+		{Version: "v18.20.4", LTS: "Hydrogen"},
+		{Version: "v16.20.2", LTS: "Gallium"},
+	}
+
+	tests := []struct {
+		request string
+		want    []string
+		wantErr bool
+	}{
+		{request: "lts/*", want: []string{"v20.14.0", "v20.9.0", "v18.20.4", "v16.20.2"}},
+		{request: "lts/iron", want: []string{"v20.14.0", "v20.9.0"}},
+		{request: "LTS/Hydrogen", want: []string{"v18.20.4"}},
+		{request: "lts/argon", wantErr: true},
+		{request: "20", want: []string{"v20.14.0", "v20.9.0", "v20.8.0"}},
+		{request: "v20.8", want: []string{"v20.8.0"}},
+		{request: "22", want: []string{"v22.2.0"}},
+		{request: "v22.0.0-rc.1", want: []string{"v22.0.0-rc.1"}},
+		{request: "^18 || >=22", want: []string{"v22.2.0", "v18.20.4"}},
+		{request: "19", wantErr: true},
+	}
+
+	b := &BaseSDK{Name: "node"}
+	for _, tt := range tests {
+		t.Run(tt.request, func(t *testing.T) {
+			got, err := b.FindMatchingReleases(tt.request, releases, NodeJSVersionPrefixHandlers())
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("期望返回错误，实际为 %v", ReleaseVersions(got))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("FindMatchingReleases(%q) 失败: %v", tt.request, err)
+			}
+			if versions := ReleaseVersions(got); !slices.Equal(versions, tt.want) {
+				t.Errorf("FindMatchingReleases(%q) = %v，期望 %v", tt.request, versions, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifyChecksumSignature(t *testing.T) {
+	config.SetConfigFile(filepath.Join(t.TempDir(), "config.json"))
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	release := newTestEntity(t, "release")
+	other := newTestEntity(t, "other")
+	writeTestKeyring(t, config.GetDefaultKeyringPath("node"), release)
+
+	data := []byte("a1b2c3  node-v20.11.1-linux-x64.tar.xz\n")
+	detached := func(entity *openpgp.Entity) string {
+		var buf bytes.Buffer
+		if err := openpgp.ArmoredDetachSign(&buf, entity, bytes.NewReader(data), nil); err != nil {
+			t.Fatal(err)
+		}
+		return buf.String()
+	}
+	var clearsigned bytes.Buffer
+	w, err := clearsign.Encode(&clearsigned, release.PrivateKey, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Write(data)
+	w.Close()
+
+	tests := []struct {
+		name    string
+		files   map[string]string // 签名文件名 -> 内容，不存在时返回404
+		wantErr bool
+	}{
+		{name: "分离签名", files: map[string]string{".sig": detached(release)}},
+		{name: "分离签名无效时使用明文签名", files: map[string]string{".sig": "invalid", ".asc": clearsigned.String()}},
+		{name: "签名者不在公钥环中时使用明文签名", files: map[string]string{".sig": detached(other), ".asc": clearsigned.String()}},
+		{name: "没有分离签名", files: map[string]string{".asc": clearsigned.String()}},
+		{name: "全部无效", files: map[string]string{".sig": detached(other), ".asc": "invalid"}, wantErr: true},
+		{name: "没有签名文件", files: map[string]string{}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, ok := tt.files[filepath.Ext(r.URL.Path)]
+				if !ok {
+					http.NotFound(w, r)
+					return
+				}
+				w.Write([]byte(body))
+			}))
+			defer server.Close()
+
+			b := &BaseSDK{
+				Name:     "node",
+				Config:   cfg,
+				Provider: &NodeSDKProvider{mirrors: Mirrors{Archives: NewMirror(server.URL)}},
+			}
+			checksumURL := server.URL + "/SHASUMS256.txt"
+			file := ReleaseFile{ChecksumURL: checksumURL, SignatureURLs: []string{checksumURL + ".sig", checksumURL + ".asc"}}
+
+			got, err := b.verifyChecksumSignature(file, data)
+			if tt.wantErr {
+				var signatureErr *utils.SignatureError
+				if !errors.As(err, &signatureErr) {
+					t.Fatalf("期望 *utils.SignatureError，实际为 %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("验证签名失败: %v", err)
+			}
+			if !bytes.Equal(bytes.TrimSpace(got), bytes.TrimSpace(data)) {
+				t.Errorf("verifyChecksumSignature() = %q，期望 %q", got, data)
+			}
+		})
+	}
+}
+
+// newTestEntity 生成测试用的OpenPGP密钥
+func newTestEntity(t *testing.T, name string) *openpgp.Entity {
+	t.Helper()
+	entity, err := openpgp.NewEntity(name, "", name+"@example.com", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return entity
+}
+
+// writeTestKeyring 将公钥以ASCII armor格式写入公钥环文件
+func writeTestKeyring(t *testing.T, path string, entity *openpgp.Entity) {
+	t.Helper()
+	var buf bytes.Buffer
+	w, err := armor.Encode(&buf, openpgp.PublicKeyType, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := entity.Serialize(w); err != nil {
+		t.Fatal(err)
+	}
+	w.Close()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCheckVersionName(t *testing.T) {
+	tests := []struct {
+		version string
+		valid   bool
+	}{
+		{"1.22.3", true},
+		{"v20.11.1", true},
+		{"17.0.9+9", true},
+		{"internal-1.22.3", true},
+		{"", false},
+		{"current", false},
+		{".locks", false},
+		{"..", false},
+		{"../x", false},
+		{"v../x", false},
+		{"1.22..3", false},
+		{"a/b", false},
+		{`a\b`, false},
+		{`C:x`, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			if err := checkVersionName(tt.version); (err == nil) != tt.valid {
+				t.Errorf("checkVersionName(%q) = %v，期望有效: %v", tt.version, err, tt.valid)
+			}
+		})
+	}
+}
@@ -0,0 +1,189 @@
+package sdk
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"svm/internal/utils"
+
+	"github.com/gofrs/flock"
+)
+
+// stagingPrefix 安装使用的临时目录名前缀。
+// 临时目录位于SDK安装目录中，保证最后可以直接重命名到位
+const stagingPrefix = ".staging-"
+
+// stagingLockName 临时目录中的锁文件，安装期间一直持有，其他进程据此判断临时目录是否仍在使用
+const stagingLockName = ".lock"
+
+// versionLayout 由版本目录不直接位于SDK安装目录下的SDK实现
+type versionLayout interface {
+	// VersionPath 返回版本目录相对SDK安装目录的路径
+	VersionPath(version string) string
+}
+
+// versionPath 返回版本目录相对SDK安装目录的路径
+func (b *BaseSDK) versionPath(version string) string {
+	if layout, ok := b.Provider.(versionLayout); ok {
+		return layout.VersionPath(version)
+	}
+	return version
+}
+
+// createStagingDir 创建安装使用的临时目录，返回临时目录、其中用于解压的版本目录和释放函数。
+// 安装期间持有临时目录中锁文件的锁，释放函数解锁并删除临时目录。同时清理之前中断的安装留下的临时目录
+func (b *BaseSDK) createStagingDir(version string) (stagingRoot, versionDir string, release func(), err error) {
+	if err := os.MkdirAll(b.InstallDir, 0755); err != nil {
+		return "", "", nil, fmt.Errorf("创建安装目录失败: %w", err)
+	}
+
+	// 清理和创建临时目录期间持有锁，避免清理掉其他进程刚创建、尚未加锁的临时目录
+	unlock, err := b.lockStaging()
+	if err != nil {
+		return "", "", nil, err
+	}
+	defer unlock()
+	b.cleanStaleStaging()
+
+	stagingRoot, err = os.MkdirTemp(b.InstallDir, stagingPrefix)
+	if err != nil {
+		return "", "", nil, fmt.Errorf("创建临时目录失败: %w", err)
+	}
+
+	lock := flock.New(filepath.Join(stagingRoot, stagingLockName))
+	if locked, err := lock.TryLock(); err != nil || !locked {
+		removeStaging(stagingRoot)
+		if err == nil {
+			err = fmt.Errorf("锁已被占用")
+		}
+		return "", "", nil, fmt.Errorf("锁定临时目录失败: %w", err)
+	}
+	release = func() {
+		if err := lock.Unlock(); err != nil {
+			utils.Log.Warning(fmt.Sprintf("释放临时目录锁失败: %v", err))
+		}
+		removeStaging(stagingRoot)
+	}
+
+	versionDir = filepath.Join(stagingRoot, version)
+	if err := os.MkdirAll(versionDir, 0755); err != nil {
+		release()
+		return "", "", nil, fmt.Errorf("创建临时目录失败: %w", err)
+	}
+	return stagingRoot, versionDir, release, nil
+}
+
+// commitStaging 将临时目录中安装完成的版本目录重命名到位并返回最终的目录。
+// 重新安装时已有的目录先移入临时目录，新目录就位后随临时目录一起删除，重命名失败时恢复
+func (b *BaseSDK) commitStaging(version, stagingRoot string) (string, error) {
+	rel := b.versionPath(version)
+	stagedDir := filepath.Join(stagingRoot, rel)
+	if exists, _ := utils.CheckDirExists(stagedDir); !exists {
+		return "", fmt.Errorf("安装不完整，未找到版本目录: %s", rel)
+	}
+
+	finalDir := filepath.Join(b.InstallDir, rel)
+	if err := os.MkdirAll(filepath.Dir(finalDir), 0755); err != nil {
+		return "", fmt.Errorf("创建安装目录失败: %w", err)
+	}
+
+	var previousDir string
+	if _, err := os.Lstat(finalDir); err == nil {
+		previousDir = filepath.Join(stagingRoot, ".previous")
+		if err := os.Rename(finalDir, previousDir); err != nil {
+			return "", fmt.Errorf("移动已有的安装目录失败: %w", err)
+		}
+	}
+
+	if err := os.Rename(stagedDir, finalDir); err != nil {
+		if previousDir != "" {
+			if restoreErr := os.Rename(previousDir, finalDir); restoreErr != nil {
+				utils.Log.Warning(fmt.Sprintf("恢复原来的安装目录失败，原目录位于 %s: %v", previousDir, restoreErr))
+			}
+		}
+		return "", fmt.Errorf("移动安装目录失败: %w", err)
+	}
+	return finalDir, nil
+}
+
+// lockVersion 获取版本的安装锁，同一版本的并行安装依次进行，返回释放锁的函数。
+// 锁文件位于SDK安装目录的 .locks 目录中
+func (b *BaseSDK) lockVersion(version string) (func(), error) {
+	lockDir := filepath.Join(b.InstallDir, ".locks")
+	if err := os.MkdirAll(lockDir, 0755); err != nil {
+		return nil, fmt.Errorf("创建锁目录失败: %w", err)
+	}
+
+	lock := flock.New(filepath.Join(lockDir, version+".lock"))
+	locked, err := lock.TryLock()
+	if err != nil {
+		return nil, fmt.Errorf("获取安装锁失败: %w", err)
+	}
+	if !locked {
+		utils.Log.Info(fmt.Sprintf("%s %s 正在由其他进程安装，等待完成...", b.Name, version))
+		if err := lock.Lock(); err != nil {
+			return nil, fmt.Errorf("获取安装锁失败: %w", err)
+		}
+	}
+
+	return func() {
+		if err := lock.Unlock(); err != nil {
+			utils.Log.Warning(fmt.Sprintf("释放安装锁失败: %v", err))
+		}
+	}, nil
+}
+
+// removeStaging 删除临时目录
+func removeStaging(stagingRoot string) {
+	if err := os.RemoveAll(stagingRoot); err != nil {
+		utils.Log.Warning(fmt.Sprintf("删除临时目录 %s 失败: %v", stagingRoot, err))
+	}
+}
+
+// lockStaging 获取创建和清理临时目录使用的锁，返回释放锁的函数
+func (b *BaseSDK) lockStaging() (func(), error) {
+	lockDir := filepath.Join(b.InstallDir, ".locks")
+	if err := os.MkdirAll(lockDir, 0755); err != nil {
+		return nil, fmt.Errorf("创建锁目录失败: %w", err)
+	}
+
+	// 版本名称不能以 . 开头，不会与版本的安装锁重名
+	lock := flock.New(filepath.Join(lockDir, ".staging.lock"))
+	if err := lock.Lock(); err != nil {
+		return nil, fmt.Errorf("获取临时目录锁失败: %w", err)
+	}
+
+	return func() {
+		if err := lock.Unlock(); err != nil {
+			utils.Log.Warning(fmt.Sprintf("释放临时目录锁失败: %v", err))
+		}
+	}, nil
+}
+
+// cleanStaleStaging 删除中断的安装留下的临时目录。安装期间一直持有临时目录中锁文件的锁，
+// 能获取到锁说明创建它的安装已经结束；锁被持有或无法打开锁文件（例如属于其他用户）时保持不变。
+// 需要在持有 lockStaging 的锁时调用
+func (b *BaseSDK) cleanStaleStaging() {
+	entries, err := os.ReadDir(b.InstallDir)
+	if err != nil {
+		return
+	}
+
+	for _, entry := range entries {
+		if !strings.HasPrefix(entry.Name(), stagingPrefix) || !entry.IsDir() {
+			continue
+		}
+
+		dir := filepath.Join(b.InstallDir, entry.Name())
+		lock := flock.New(filepath.Join(dir, stagingLockName))
+		if locked, err := lock.TryLock(); err != nil || !locked {
+			continue
+		}
+		// 先释放锁再删除，Windows 上无法删除已打开的文件
+		lock.Unlock()
+
+		utils.Log.Delete(fmt.Sprintf("清理未完成的安装: %s", dir))
+		removeStaging(dir)
+	}
+}
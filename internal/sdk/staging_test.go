@@ -0,0 +1,54 @@
+package sdk
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCleanStaleStaging(t *testing.T) {
+	b := &BaseSDK{Name: "go", InstallDir: t.TempDir()}
+
+	// 中断的安装：没有锁文件，或锁文件未被持有
+	stale := []string{
+		filepath.Join(b.InstallDir, stagingPrefix+"1-123"),
+		filepath.Join(b.InstallDir, stagingPrefix+"456"),
+	}
+	for _, dir := range stale {
+		if err := os.MkdirAll(filepath.Join(dir, "1.22.3"), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(stale[1], stagingLockName), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// 本进程中正在进行的安装
+	running, _, release, err := b.createStagingDir("1.22.3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer release()
+
+	// 再次创建临时目录时清理中断的安装，正在进行的安装保持不变
+	other, _, releaseOther, err := b.createStagingDir("1.21.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, dir := range stale {
+		if _, err := os.Stat(dir); !os.IsNotExist(err) {
+			t.Errorf("中断的安装 %s 没有被清理", dir)
+		}
+	}
+	for _, dir := range []string{running, other} {
+		if _, err := os.Stat(dir); err != nil {
+			t.Errorf("正在进行的安装 %s 被删除: %v", dir, err)
+		}
+	}
+
+	releaseOther()
+	if _, err := os.Stat(other); !os.IsNotExist(err) {
+		t.Errorf("释放后临时目录 %s 仍然存在", other)
+	}
+}
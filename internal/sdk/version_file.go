@@ -0,0 +1,58 @@
+package sdk
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// readVersionFileLine 读取版本文件中第一行有效内容，忽略空行和#注释
+func readVersionFileLine(filePath string) (string, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if idx := strings.Index(line, "#"); idx >= 0 {
+			line = line[:idx]
+		}
+		line = strings.TrimSpace(line)
+		if line != "" {
+			return line, nil
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+	return "", fmt.Errorf("版本文件为空")
+}
+
+// readVersionFileProperty 读取 key=value 格式版本文件中指定键的值
+func readVersionFileProperty(filePath, key string) (string, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		name, value, ok := strings.Cut(line, "=")
+		if ok && strings.TrimSpace(name) == key {
+			return strings.TrimSpace(value), nil
+		}
+	}
+
+	return "", scanner.Err()
+}
@@ -3,14 +3,65 @@ package utils
 import (
 	"archive/tar"
 	"archive/zip"
+	"bufio"
+	"bytes"
 	"compress/gzip"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
 	"runtime"
+	"strings"
+	"time"
+
+	"github.com/ulikunitz/xz"
+)
+
+// 归档类型
+const (
+	ArchiveZip     = "zip"
+	ArchiveTarGz   = "tar.gz"
+	ArchiveTarXz   = "tar.xz"
+	ArchiveNone    = "none"    // 不需要解压的文件，例如 .exe、.msi 安装程序
+	ArchiveUnknown = "unknown" // 无法识别的文件
 )
 
+// DetectArchiveType 根据文件名判断归档类型，无法从文件名判断时（例如自定义地址下载的文件）读取文件头
+func DetectArchiveType(path string) string {
+	name := strings.ToLower(filepath.Base(path))
+	switch {
+	case strings.HasSuffix(name, ".zip"):
+		return ArchiveZip
+	case strings.HasSuffix(name, ".tar.gz"), strings.HasSuffix(name, ".tgz"):
+		return ArchiveTarGz
+	case strings.HasSuffix(name, ".tar.xz"), strings.HasSuffix(name, ".txz"):
+		return ArchiveTarXz
+	case strings.HasSuffix(name, ".exe"), strings.HasSuffix(name, ".msi"):
+		return ArchiveNone
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return ArchiveUnknown
+	}
+	defer file.Close()
+
+	header := make([]byte, 6)
+	n, _ := io.ReadFull(file, header)
+	header = header[:n]
+	switch {
+	case bytes.HasPrefix(header, []byte("PK\x03\x04")):
+		return ArchiveZip
+	case bytes.HasPrefix(header, []byte{0x1f, 0x8b}):
+		return ArchiveTarGz
+	case bytes.HasPrefix(header, []byte{0xfd, '7', 'z', 'X', 'Z', 0x00}):
+		return ArchiveTarXz
+	case bytes.HasPrefix(header, []byte("MZ")):
+		return ArchiveNone
+	}
+	return ArchiveUnknown
+}
+
 // ExtractTarGz 解压tar.gz文件
 func ExtractTarGz(gzipStream io.Reader, destPath string) error {
 	uncompressedStream, err := gzip.NewReader(gzipStream)
@@ -19,7 +70,29 @@ func ExtractTarGz(gzipStream io.Reader, destPath string) error {
 	}
 	defer uncompressedStream.Close()
 
-	tarReader := tar.NewReader(uncompressedStream)
+	return extractTar(uncompressedStream, destPath)
+}
+
+// ExtractTarXz 解压tar.xz文件
+func ExtractTarXz(xzStream io.Reader, destPath string) error {
+	uncompressedStream, err := xz.NewReader(bufio.NewReader(xzStream))
+	if err != nil {
+		return fmt.Errorf("创建xz reader失败: %w", err)
+	}
+
+	return extractTar(uncompressedStream, destPath)
+}
+
+// extractTar 解压未压缩的tar流，保留文件权限、修改时间、符号链接和硬链接。
+// 条目位于目标目录之外或超过解压限制时返回 *UnsafeArchiveError
+func extractTar(stream io.Reader, destPath string) error {
+	guard, err := newExtractGuard(destPath)
+	if err != nil {
+		return err
+	}
+
+	var dirs []dirTime
+	tarReader := tar.NewReader(stream)
 	for {
 		header, err := tarReader.Next()
 		if err == io.EOF {
@@ -28,44 +101,80 @@ func ExtractTarGz(gzipStream io.Reader, destPath string) error {
 		if err != nil {
 			return fmt.Errorf("读取tar文件失败: %w", err)
 		}
+		if header.Typeflag == tar.TypeXGlobalHeader {
+			// 全局扩展头只包含元数据，没有需要写入的内容
+			continue
+		}
 
 		// 获取文件路径
-		path := filepath.Join(destPath, header.Name)
+		path, err := guard.entryPath(header.Name, header.Size)
+		if err != nil {
+			return err
+		}
+		mode := header.FileInfo().Mode().Perm()
 
 		switch header.Typeflag {
 		case tar.TypeDir:
-			if err := os.MkdirAll(path, 0755); err != nil {
-				return fmt.Errorf("创建目录失败: %w", err)
+			if err := makeDir(path, mode); err != nil {
+				return err
 			}
+			dirs = append(dirs, dirTime{path: path, modTime: header.ModTime})
 		case tar.TypeReg:
-			outFile, err := createFile(path)
+			if err := writeFile(path, guard.reader(tarReader), mode, header.ModTime); err != nil {
+				return err
+			}
+		case tar.TypeSymlink:
+			if err := guard.checkSymlink(header.Name, path, header.Linkname); err != nil {
+				return err
+			}
+			if err := makeSymlink(header.Linkname, path); err != nil {
+				return err
+			}
+		case tar.TypeLink:
+			target, err := guard.resolve(header.Linkname)
 			if err != nil {
 				return err
 			}
-			if _, err := io.Copy(outFile, tarReader); err != nil {
-				outFile.Close()
-				return fmt.Errorf("写入文件失败: %w", err)
+			if err := makeHardlink(target, path); err != nil {
+				return err
 			}
-			outFile.Close()
 		default:
 			Log.Warning(fmt.Sprintf("未处理的tar类型: %c in file %s", header.Typeflag, path))
 		}
 	}
-	return nil
+	return setDirTimes(dirs)
 }
 
 // ExtractTarGzFile 解压tar.gz文件，接受文件路径作为参数
 func ExtractTarGzFile(tarGzPath string, destPath string) error {
-	file, err := os.Open(tarGzPath)
+	return extractCompressedTarFile(tarGzPath, destPath, ExtractTarGz)
+}
+
+// ExtractTarXzFile 解压tar.xz文件，接受文件路径作为参数
+func ExtractTarXzFile(tarXzPath string, destPath string) error {
+	return extractCompressedTarFile(tarXzPath, destPath, ExtractTarXz)
+}
+
+// extractCompressedTarFile 打开压缩的tar文件并显示进度，解压后的总大小未知，按已读取的压缩文件大小显示
+func extractCompressedTarFile(archivePath, destPath string, extract func(io.Reader, string) error) error {
+	file, err := os.Open(archivePath)
 	if err != nil {
 		return fmt.Errorf("打开文件失败: %w", err)
 	}
 	defer file.Close()
 
-	return ExtractTarGz(file, destPath)
+	total := int64(-1)
+	if info, err := file.Stat(); err == nil {
+		total = info.Size()
+	}
+	progress := NewProgress("解压 "+filepath.Base(archivePath), total)
+	defer progress.Finish()
+
+	return extract(io.TeeReader(file, progress), destPath)
 }
 
-// ExtractZip 解压zip文件
+// ExtractZip 解压zip文件，保留Unix权限、修改时间和符号链接。
+// 条目位于目标目录之外或超过解压限制时返回 *UnsafeArchiveError
 func ExtractZip(zipPath, destPath string) error {
 	reader, err := zip.OpenReader(zipPath)
 	if err != nil {
@@ -73,29 +182,42 @@ func ExtractZip(zipPath, destPath string) error {
 	}
 	defer reader.Close()
 
+	guard, err := newExtractGuard(destPath)
+	if err != nil {
+		return err
+	}
+
+	// 按解压后的大小显示进度
+	var total int64
 	for _, file := range reader.File {
-		err := extractZipFile(file, destPath)
+		total += int64(file.UncompressedSize64)
+	}
+	progress := NewProgress("解压 "+filepath.Base(zipPath), total)
+	defer progress.Finish()
+
+	var dirs []dirTime
+	for _, file := range reader.File {
+		path, err := guard.entryPath(file.Name, int64(file.UncompressedSize64))
 		if err != nil {
 			return err
 		}
+		if err := extractZipFile(file, path, guard, progress); err != nil {
+			return err
+		}
+		if file.FileInfo().IsDir() {
+			dirs = append(dirs, dirTime{path: path, modTime: file.Modified})
+		}
 	}
-	return nil
+	return setDirTimes(dirs)
 }
 
-// extractZipFile 解压单个zip文件
-func extractZipFile(file *zip.File, destPath string) error {
-	// 检查文件是否是一个目录
-	if file.FileInfo().IsDir() {
-		path := filepath.Join(destPath, file.Name)
-		return os.MkdirAll(path, 0755)
-	}
+// extractZipFile 将单个zip条目解压到 path，写入的字节数计入 progress
+func extractZipFile(file *zip.File, path string, guard *extractGuard, progress *Progress) error {
+	mode := file.Mode()
 
-	// 获取文件路径
-	path := filepath.Join(destPath, file.Name)
-
-	// 确保文件的目录存在
-	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
-		return fmt.Errorf("创建目录失败: %w", err)
+	// 检查文件是否是一个目录
+	if mode.IsDir() {
+		return makeDir(path, zipPerm(file, 0755))
 	}
 
 	// 打开源文件
@@ -104,32 +226,144 @@ func extractZipFile(file *zip.File, destPath string) error {
 		return fmt.Errorf("打开zip文件失败: %w", err)
 	}
 	defer srcFile.Close()
+	src := io.TeeReader(guard.reader(srcFile), progress)
 
-	// 创建目标文件
-	dstFile, err := os.Create(path)
-	if err != nil {
-		return fmt.Errorf("创建文件失败: %w", err)
+	// 符号链接的内容是链接目标
+	if mode&os.ModeSymlink != 0 {
+		target, err := io.ReadAll(src)
+		if err != nil {
+			return fmt.Errorf("读取符号链接失败: %w", err)
+		}
+		if err := guard.checkSymlink(file.Name, path, string(target)); err != nil {
+			return err
+		}
+		return makeSymlink(string(target), path)
 	}
-	defer dstFile.Close()
 
-	// 复制内容
-	_, err = io.Copy(dstFile, srcFile)
+	return writeFile(path, src, zipPerm(file, 0644), file.Modified)
+}
+
+// zipPerm 返回zip条目的权限，Windows等系统创建的zip没有Unix权限，使用默认权限
+func zipPerm(file *zip.File, fallback os.FileMode) os.FileMode {
+	switch file.CreatorVersion >> 8 {
+	case zipCreatorUnix, zipCreatorMacOSX:
+		return file.Mode().Perm()
+	}
+	return fallback
+}
+
+// zip中记录创建系统的标识，只有这些系统创建的zip包含Unix权限
+const (
+	zipCreatorUnix   = 3
+	zipCreatorMacOSX = 19
+)
+
+// dirTime 记录解压的目录及其修改时间，目录中的文件全部写入后再设置
+type dirTime struct {
+	path    string
+	modTime time.Time
+}
+
+// makeDir 创建目录并设置权限，所有者始终保留读写和进入权限，以便写入目录中的文件
+func makeDir(path string, mode os.FileMode) error {
+	if err := os.MkdirAll(path, 0755); err != nil {
+		return fmt.Errorf("创建目录失败: %w", err)
+	}
+	if err := os.Chmod(path, mode|0700); err != nil {
+		return fmt.Errorf("设置目录权限失败: %w", err)
+	}
+	return nil
+}
+
+// writeFile 将内容写入文件并设置权限和修改时间，已存在的文件或链接会先删除
+func writeFile(path string, r io.Reader, mode os.FileMode, modTime time.Time) error {
+	if err := prepareEntry(path); err != nil {
+		return err
+	}
+
+	outFile, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
 	if err != nil {
+		return fmt.Errorf("创建文件失败: %w", err)
+	}
+	if _, err := io.Copy(outFile, r); err != nil {
+		outFile.Close()
+		return fmt.Errorf("写入文件失败: %w", err)
+	}
+	if err := outFile.Close(); err != nil {
 		return fmt.Errorf("写入文件失败: %w", err)
 	}
 
+	// 创建文件时的权限受 umask 影响，这里按归档中的权限重新设置
+	if err := os.Chmod(path, mode); err != nil {
+		return fmt.Errorf("设置文件权限失败: %w", err)
+	}
+	return setModTime(path, modTime)
+}
+
+// makeSymlink 创建符号链接，链接目标保持归档中的原样
+func makeSymlink(target, path string) error {
+	if err := prepareEntry(path); err != nil {
+		return err
+	}
+	if err := os.Symlink(target, path); err != nil {
+		return fmt.Errorf("创建符号链接失败: %w", err)
+	}
 	return nil
 }
 
-// createFile 创建文件，确保文件的父目录存在
-func createFile(path string) (*os.File, error) {
-	// 确保目录存在
-	err := os.MkdirAll(filepath.Dir(path), 0755)
+// makeHardlink 创建硬链接，文件系统不支持硬链接时复制文件
+func makeHardlink(target, path string) error {
+	if err := prepareEntry(path); err != nil {
+		return err
+	}
+	if err := os.Link(target, path); err == nil {
+		return nil
+	}
+
+	info, err := os.Stat(target)
+	if err != nil {
+		return fmt.Errorf("硬链接的目标不存在: %w", err)
+	}
+	srcFile, err := os.Open(target)
 	if err != nil {
-		return nil, fmt.Errorf("创建目录失败: %w", err)
+		return fmt.Errorf("打开文件失败: %w", err)
+	}
+	defer srcFile.Close()
+	return writeFile(path, srcFile, info.Mode().Perm(), info.ModTime())
+}
+
+// prepareEntry 确保父目录存在，并删除同名的已有文件或链接
+func prepareEntry(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("创建目录失败: %w", err)
 	}
-	// 创建文件
-	return os.Create(path)
+	if info, err := os.Lstat(path); err == nil && !info.IsDir() {
+		if err := os.Remove(path); err != nil {
+			return fmt.Errorf("删除已存在的文件失败: %w", err)
+		}
+	}
+	return nil
+}
+
+// setModTime 设置文件的修改时间，归档中没有时间时保持不变
+func setModTime(path string, modTime time.Time) error {
+	if modTime.IsZero() {
+		return nil
+	}
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		return fmt.Errorf("设置修改时间失败: %w", err)
+	}
+	return nil
+}
+
+// setDirTimes 按与归档相反的顺序设置目录的修改时间，子目录先于父目录
+func setDirTimes(dirs []dirTime) error {
+	for i := len(dirs) - 1; i >= 0; i-- {
+		if err := setModTime(dirs[i].path, dirs[i].modTime); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // ExtractExe 处理Windows可执行安装程序
@@ -0,0 +1,166 @@
+package utils
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// 解压的限制，用于防止解压炸弹。SDK中最大的 .NET SDK 和 JDK 解压后约 1GB、数万个文件
+var (
+	MaxExtractSize  int64 = 8 << 30 // 解压后的最大总大小
+	MaxExtractFiles       = 200000  // 最大条目数
+)
+
+// UnsafeArchiveError 表示归档中包含会写到目标目录之外的条目，或解压后的大小、条目数超过限制
+type UnsafeArchiveError struct {
+	Entry  string // 条目名称，超过限制时为空
+	Reason string // 原因
+}
+
+func (e *UnsafeArchiveError) Error() string {
+	if e.Entry == "" {
+		return fmt.Sprintf("不安全的归档: %s", e.Reason)
+	}
+	return fmt.Sprintf("不安全的归档条目 %s: %s", e.Entry, e.Reason)
+}
+
+// extractGuard 检查解压的每个条目，确保所有文件和链接都位于目标目录中，并统计解压的大小和条目数
+type extractGuard struct {
+	root     string // 目标目录
+	realRoot string // 解析符号链接后的目标目录
+	size     int64
+	files    int
+}
+
+// newExtractGuard 为目标目录创建检查器，目标目录不存在时先创建
+func newExtractGuard(destPath string) (*extractGuard, error) {
+	root, err := filepath.Abs(destPath)
+	if err != nil {
+		return nil, fmt.Errorf("获取绝对路径失败: %w", err)
+	}
+	if err := os.MkdirAll(root, 0755); err != nil {
+		return nil, fmt.Errorf("创建目录失败: %w", err)
+	}
+	realRoot, err := filepath.EvalSymlinks(root)
+	if err != nil {
+		return nil, fmt.Errorf("解析目录失败: %w", err)
+	}
+	return &extractGuard{root: root, realRoot: realRoot}, nil
+}
+
+// entryPath 检查条目名称并返回解压后的路径，声明的大小计入总大小
+func (g *extractGuard) entryPath(name string, size int64) (string, error) {
+	g.files++
+	if g.files > MaxExtractFiles {
+		return "", &UnsafeArchiveError{Reason: fmt.Sprintf("条目数超过 %d", MaxExtractFiles)}
+	}
+	if size > 0 && g.size+size > MaxExtractSize {
+		return "", g.sizeError()
+	}
+	return g.resolve(name)
+}
+
+// resolve 检查条目名称或硬链接目标并返回对应的路径
+func (g *extractGuard) resolve(name string) (string, error) {
+	if name == "" || filepath.IsAbs(name) || filepath.VolumeName(name) != "" || strings.HasPrefix(name, "/") || strings.HasPrefix(name, `\`) {
+		return "", &UnsafeArchiveError{Entry: name, Reason: "不允许使用绝对路径"}
+	}
+
+	path := filepath.Join(g.root, name)
+	if !IsWithin(g.root, path) {
+		return "", &UnsafeArchiveError{Entry: name, Reason: "路径位于目标目录之外"}
+	}
+
+	// 已解压的符号链接可能让父目录指向目标目录之外
+	if parent := realPath(filepath.Dir(path)); !IsWithin(g.realRoot, parent) {
+		return "", &UnsafeArchiveError{Entry: name, Reason: "父目录是指向目标目录之外的符号链接"}
+	}
+	return path, nil
+}
+
+// checkSymlink 检查符号链接的目标，链接只能指向目标目录中的位置
+func (g *extractGuard) checkSymlink(name, path, target string) error {
+	if filepath.IsAbs(target) || filepath.VolumeName(target) != "" || strings.HasPrefix(target, "/") || strings.HasPrefix(target, `\`) {
+		return &UnsafeArchiveError{Entry: name, Reason: fmt.Sprintf("符号链接指向绝对路径 %s", target)}
+	}
+	resolved, err := resolveLinkTarget(realPath(filepath.Dir(path)), target)
+	if err != nil {
+		return &UnsafeArchiveError{Entry: name, Reason: err.Error()}
+	}
+	if !IsWithin(g.realRoot, resolved) {
+		return &UnsafeArchiveError{Entry: name, Reason: fmt.Sprintf("符号链接指向目标目录之外的 %s", target)}
+	}
+	return nil
+}
+
+// resolveLinkTarget 从 dir 开始逐个解析链接目标的各部分，已解压的符号链接按实际指向解析，
+// 不能先拼接再按字面化简（q -> . 时 q/.. 是 dir 的父目录）。
+// 不存在的部分之后不允许 ..，因为之后解压的条目可能把该部分变成符号链接
+func resolveLinkTarget(dir, target string) (string, error) {
+	current, exists := dir, true
+	parts := strings.FieldsFunc(target, func(r rune) bool { return r == '/' || r == '\\' })
+	for _, part := range parts {
+		switch part {
+		case ".":
+		case "..":
+			if !exists {
+				return "", fmt.Errorf("符号链接 %s 在尚不存在的路径之后使用 ..", target)
+			}
+			current = filepath.Dir(current)
+		default:
+			current = filepath.Join(current, part)
+			if !exists {
+				continue
+			}
+			if resolved, err := filepath.EvalSymlinks(current); err == nil {
+				current = resolved
+			} else {
+				exists = false
+			}
+		}
+	}
+	return current, nil
+}
+
+// reader 返回统计实际解压大小的 Reader，超过限制时返回 *UnsafeArchiveError
+func (g *extractGuard) reader(r io.Reader) io.Reader {
+	return &guardedReader{r: r, guard: g}
+}
+
+func (g *extractGuard) sizeError() error {
+	return &UnsafeArchiveError{Reason: fmt.Sprintf("解压后的大小超过 %s", FormatBytes(MaxExtractSize))}
+}
+
+// guardedReader 统计读取的字节数
+type guardedReader struct {
+	r     io.Reader
+	guard *extractGuard
+}
+
+func (r *guardedReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.guard.size += int64(n)
+	if r.guard.size > MaxExtractSize {
+		return n, r.guard.sizeError()
+	}
+	return n, err
+}
+
+// realPath 解析路径中已存在部分的符号链接，不存在的部分原样保留
+func realPath(path string) string {
+	var rest []string
+	for {
+		if resolved, err := filepath.EvalSymlinks(path); err == nil {
+			return filepath.Join(append([]string{resolved}, rest...)...)
+		}
+		parent := filepath.Dir(path)
+		if parent == path {
+			return filepath.Join(append([]string{path}, rest...)...)
+		}
+		rest = append([]string{filepath.Base(path)}, rest...)
+		path = parent
+	}
+}
@@ -0,0 +1,209 @@
+package utils
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// archiveEntry 描述测试归档中的一个条目，link 不为空时是符号链接
+type archiveEntry struct {
+	name string
+	link string
+	body string
+}
+
+func TestExtractGuard(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []archiveEntry
+		unsafe  bool
+	}{
+		{
+			name: "相对链接",
+			entries: []archiveEntry{
+				{name: "lib/x", body: "x"},
+				{name: "bin/npm", link: "../lib/x"},
+			},
+		},
+		{
+			name:    "指向同级目录的链接",
+			entries: []archiveEntry{{name: "current", link: "./lib"}},
+		},
+		{
+			name:    "路径穿越",
+			entries: []archiveEntry{{name: "../evil", body: "x"}},
+			unsafe:  true,
+		},
+		{
+			name:    "绝对路径",
+			entries: []archiveEntry{{name: "/tmp/evil", body: "x"}},
+			unsafe:  true,
+		},
+		{
+			name:    "链接指向绝对路径",
+			entries: []archiveEntry{{name: "p", link: "/etc"}},
+			unsafe:  true,
+		},
+		{
+			name:    "链接指向目标目录之外",
+			entries: []archiveEntry{{name: "a/p", link: "../../etc"}},
+			unsafe:  true,
+		},
+		{
+			name: "经过已解压链接的 ..",
+			entries: []archiveEntry{
+				{name: "q", link: "."},
+				{name: "p", link: "q/.."},
+			},
+			unsafe: true,
+		},
+		{
+			name: "经过已解压链接的 .. 后的路径",
+			entries: []archiveEntry{
+				{name: "q", link: "."},
+				{name: "p", link: "q/../etc"},
+			},
+			unsafe: true,
+		},
+		{
+			name: "之后才解压的链接",
+			entries: []archiveEntry{
+				{name: "a", link: "b/.."},
+				{name: "b", link: "."},
+			},
+			unsafe: true,
+		},
+	}
+
+	formats := []struct {
+		name    string
+		extract func(t *testing.T, entries []archiveEntry, dest string) error
+	}{
+		{name: "tar.gz", extract: extractTestTarGz},
+		{name: "zip", extract: extractTestZip},
+	}
+
+	for _, format := range formats {
+		for _, tt := range tests {
+			t.Run(format.name+"/"+tt.name, func(t *testing.T) {
+				dest := filepath.Join(t.TempDir(), "dest")
+				err := format.extract(t, tt.entries, dest)
+
+				var unsafeErr *UnsafeArchiveError
+				if tt.unsafe {
+					if !errors.As(err, &unsafeErr) {
+						t.Fatalf("期望 *UnsafeArchiveError，实际为 %v", err)
+					}
+					return
+				}
+				if err != nil {
+					t.Fatalf("解压失败: %v", err)
+				}
+			})
+		}
+	}
+}
+
+func TestExtractGuardLimits(t *testing.T) {
+	oldSize, oldFiles := MaxExtractSize, MaxExtractFiles
+	defer func() { MaxExtractSize, MaxExtractFiles = oldSize, oldFiles }()
+
+	tests := []struct {
+		name     string
+		maxSize  int64
+		maxFiles int
+		entries  []archiveEntry
+	}{
+		{
+			name:     "大小超过限制",
+			maxSize:  4,
+			maxFiles: 10,
+			entries:  []archiveEntry{{name: "a", body: "12345"}},
+		},
+		{
+			name:     "条目数超过限制",
+			maxSize:  1 << 20,
+			maxFiles: 1,
+			entries:  []archiveEntry{{name: "a", body: "1"}, {name: "b", body: "2"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			MaxExtractSize, MaxExtractFiles = tt.maxSize, tt.maxFiles
+			err := extractTestTarGz(t, tt.entries, t.TempDir())
+
+			var unsafeErr *UnsafeArchiveError
+			if !errors.As(err, &unsafeErr) {
+				t.Fatalf("期望 *UnsafeArchiveError，实际为 %v", err)
+			}
+		})
+	}
+}
+
+// extractTestTarGz 生成包含 entries 的 tar.gz 并解压到 dest
+func extractTestTarGz(t *testing.T, entries []archiveEntry, dest string) error {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for _, entry := range entries {
+		header := &tar.Header{Name: entry.name, Mode: 0644, Typeflag: tar.TypeReg, Size: int64(len(entry.body))}
+		if entry.link != "" {
+			header = &tar.Header{Name: entry.name, Mode: 0777, Typeflag: tar.TypeSymlink, Linkname: entry.link}
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(entry.body)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return ExtractTarGz(&buf, dest)
+}
+
+// extractTestZip 生成包含 entries 的zip文件并解压到 dest
+func extractTestZip(t *testing.T, entries []archiveEntry, dest string) error {
+	t.Helper()
+	zipPath := filepath.Join(t.TempDir(), "test.zip")
+	file, err := os.Create(zipPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zw := zip.NewWriter(file)
+	for _, entry := range entries {
+		header := &zip.FileHeader{Name: entry.name, Method: zip.Deflate}
+		body := entry.body
+		header.SetMode(0644)
+		if entry.link != "" {
+			header.SetMode(os.ModeSymlink | 0777)
+			body = entry.link
+		}
+		w, err := zw.CreateHeader(header)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(body)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := file.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return ExtractZip(zipPath, dest)
+}
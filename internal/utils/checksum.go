@@ -0,0 +1,100 @@
+package utils
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ChecksumError 表示下载文件的校验值与发布方提供的不一致
+type ChecksumError struct {
+	Path      string // 文件路径
+	Algorithm string // 校验算法
+	Expected  string // 期望的校验值
+	Actual    string // 实际的校验值
+}
+
+func (e *ChecksumError) Error() string {
+	return fmt.Sprintf("文件 %s 的 %s 校验值不匹配: 期望 %s，实际 %s",
+		filepath.Base(e.Path), e.Algorithm, e.Expected, e.Actual)
+}
+
+// newHash 根据算法名称创建哈希函数
+func newHash(algorithm string) (hash.Hash, error) {
+	switch strings.ToLower(strings.ReplaceAll(algorithm, "-", "")) {
+	case "sha256":
+		return sha256.New(), nil
+	case "sha512":
+		return sha512.New(), nil
+	case "sha384":
+		return sha512.New384(), nil
+	case "sha1":
+		return sha1.New(), nil
+	}
+	return nil, fmt.Errorf("不支持的校验算法: %s", algorithm)
+}
+
+// FileChecksum 计算文件的校验值（十六进制小写）
+func FileChecksum(path, algorithm string) (string, error) {
+	h, err := newHash(algorithm)
+	if err != nil {
+		return "", err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("打开文件失败: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := io.Copy(h, file); err != nil {
+		return "", fmt.Errorf("读取文件失败: %w", err)
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// VerifyChecksum 校验文件，不一致时返回 *ChecksumError
+func VerifyChecksum(path, algorithm, expected string) error {
+	actual, err := FileChecksum(path, algorithm)
+	if err != nil {
+		return err
+	}
+
+	if !strings.EqualFold(actual, strings.TrimSpace(expected)) {
+		return &ChecksumError{
+			Path:      path,
+			Algorithm: strings.ToLower(algorithm),
+			Expected:  strings.ToLower(strings.TrimSpace(expected)),
+			Actual:    actual,
+		}
+	}
+	return nil
+}
+
+// ParseChecksumFile 从 sha256sum 格式的校验文件（如Node.js的SHASUMS256.txt）中查找指定文件的校验值
+// 每行格式为 "<校验值>  <文件名>"，二进制模式的文件名以*开头
+func ParseChecksumFile(data []byte, fileName string) (string, bool) {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			continue
+		}
+
+		name := strings.TrimPrefix(fields[1], "*")
+		if name == fileName || filepath.Base(name) == fileName {
+			return strings.ToLower(fields[0]), true
+		}
+	}
+	return "", false
+}
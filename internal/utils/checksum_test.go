@@ -0,0 +1,38 @@
+package utils
+
+import "testing"
+
+func TestParseChecksumFile(t *testing.T) {
+	data := []byte(`a1b2c3  node-v20.11.1-darwin-arm64.tar.gz
+D4E5F6  node-v20.11.1-linux-x64.tar.xz
+0a0b0c *node-v20.11.1-win-x64.zip
+112233  win-x64/node.exe
+invalid line
+445566  extra  field
+`)
+
+	tests := []struct {
+		name     string
+		fileName string
+		want     string
+		found    bool
+	}{
+		{name: "文本模式", fileName: "node-v20.11.1-darwin-arm64.tar.gz", want: "a1b2c3", found: true},
+		{name: "校验值转为小写", fileName: "node-v20.11.1-linux-x64.tar.xz", want: "d4e5f6", found: true},
+		{name: "二进制模式", fileName: "node-v20.11.1-win-x64.zip", want: "0a0b0c", found: true},
+		{name: "完整路径", fileName: "win-x64/node.exe", want: "112233", found: true},
+		{name: "只匹配文件名", fileName: "node.exe", want: "112233", found: true},
+		{name: "不存在", fileName: "node-v20.11.1-linux-arm64.tar.xz"},
+		{name: "文件名的一部分", fileName: "linux-x64.tar.xz"},
+		{name: "格式错误的行", fileName: "field"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, found := ParseChecksumFile(data, tt.fileName)
+			if got != tt.want || found != tt.found {
+				t.Errorf("ParseChecksumFile(%q) = %q, %v，期望 %q, %v", tt.fileName, got, found, tt.want, tt.found)
+			}
+		})
+	}
+}
@@ -0,0 +1,406 @@
+package utils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// comparator 表示单个版本比较条件，例如 >=1.21.0
+type comparator struct {
+	op      string  // 比较运算符：=, !=, >, >=, <, <=
+	version Version // 比较的版本号
+}
+
+// Constraint 表示一个版本约束，例如 ^18.2、~3.11.4、>=1.21 <1.23、18.x
+// 多个条件之间以空格或逗号分隔表示“且”，以 || 分隔表示“或”
+type Constraint struct {
+	raw  string
+	sets [][]comparator
+}
+
+// NoMatchError 表示没有版本满足约束
+type NoMatchError struct {
+	Constraint  string   // 原始约束
+	Available   int      // 可用版本数量
+	Lower       string   // 低于约束的最接近版本
+	Higher      string   // 高于约束的最接近版本
+	Prereleases []string // 满足约束但被排除的预发布版本
+}
+
+func (e *NoMatchError) Error() string {
+	if e.Available == 0 {
+		return fmt.Sprintf("没有满足约束 %s 的版本：可用版本列表为空", e.Constraint)
+	}
+
+	if len(e.Prereleases) > 0 {
+		return fmt.Sprintf("没有满足约束 %s 的正式版本，仅有预发布版本 %s，如需使用请在约束中指定预发布版本",
+			e.Constraint, strings.Join(e.Prereleases, ", "))
+	}
+
+	msg := fmt.Sprintf("在 %d 个可用版本中没有满足约束 %s 的版本", e.Available, e.Constraint)
+	var nearest []string
+	if e.Lower != "" {
+		nearest = append(nearest, "较低版本 "+e.Lower)
+	}
+	if e.Higher != "" {
+		nearest = append(nearest, "较高版本 "+e.Higher)
+	}
+	if len(nearest) > 0 {
+		msg += "，最接近的版本: " + strings.Join(nearest, "，")
+	}
+	return msg
+}
+
+// ParseConstraint 解析版本约束
+// 支持的写法：
+//   - 精确版本：1.22.3、v18.20.4、=1.22.3
+//   - 部分版本和通配符：18、1.22、18.x、3.11.*
+//   - 比较运算符：>=1.21 <1.23、>1.20、!=3.12.0
+//   - 插入符和波浪号：^18.2、~3.11.4
+//   - PEP 440 兼容版本：~=3.11.4
+//   - 连字符范围：1.20 - 1.22
+//   - 任意版本：*、x、latest、空字符串
+func ParseConstraint(constraint string) (*Constraint, error) {
+	c := &Constraint{raw: strings.TrimSpace(constraint)}
+
+	for _, part := range strings.Split(c.raw, "||") {
+		set, err := parseComparatorSet(strings.TrimSpace(part))
+		if err != nil {
+			return nil, fmt.Errorf("无效的版本约束 %q: %w", constraint, err)
+		}
+		c.sets = append(c.sets, set)
+	}
+
+	return c, nil
+}
+
+// String 返回原始约束字符串
+func (c *Constraint) String() string {
+	if c.raw == "" {
+		return "*"
+	}
+	return c.raw
+}
+
+// Check 检查版本是否满足约束
+// 预发布版本只有在约束中明确指定了相同版本号的预发布版本时才会匹配
+func (c *Constraint) Check(version string) bool {
+	v, err := ParseVersion(version)
+	if err != nil {
+		return false
+	}
+
+	for _, set := range c.sets {
+		if setMatches(set, v) && (!v.IsPrerelease() || setAllowsPrerelease(set, v)) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkIgnoringPrerelease 检查版本是否满足约束，不考虑预发布版本的限制
+func (c *Constraint) checkIgnoringPrerelease(version string) bool {
+	v, err := ParseVersion(version)
+	if err != nil {
+		return false
+	}
+
+	for _, set := range c.sets {
+		if setMatches(set, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchVersions 返回满足约束的所有版本（从新到旧）
+// 没有满足约束的版本时返回 *NoMatchError，说明原因
+func MatchVersions(constraint string, availableVersions []string) ([]string, error) {
+	c, err := ParseConstraint(constraint)
+	if err != nil {
+		return nil, err
+	}
+
+	sorted := GetSortedVersions(availableVersions, "")
+
+	var matches []string
+	for _, v := range sorted {
+		if c.Check(v) {
+			matches = append(matches, v)
+		}
+	}
+
+	if len(matches) > 0 {
+		return matches, nil
+	}
+
+	return nil, c.noMatchError(sorted)
+}
+
+// ResolveVersion 返回满足约束的最高版本
+func ResolveVersion(constraint string, availableVersions []string) (string, error) {
+	matches, err := MatchVersions(constraint, availableVersions)
+	if err != nil {
+		return "", err
+	}
+	return matches[0], nil
+}
+
+// noMatchError 构建没有匹配版本时的错误，sorted 需按从新到旧排序
+func (c *Constraint) noMatchError(sorted []string) *NoMatchError {
+	e := &NoMatchError{Constraint: c.String(), Available: len(sorted)}
+
+	for _, v := range sorted {
+		if c.checkIgnoringPrerelease(v) {
+			e.Prereleases = append(e.Prereleases, v)
+		}
+	}
+	if len(e.Prereleases) > 3 {
+		e.Prereleases = e.Prereleases[:3]
+	}
+
+	// 只对单一范围给出最接近的版本，|| 组合的约束难以界定上下界
+	if len(c.sets) != 1 {
+		return e
+	}
+
+	for _, v := range sorted {
+		version, err := ParseVersion(v)
+		if err != nil || version.IsPrerelease() {
+			continue
+		}
+
+		if isBelowSet(c.sets[0], version) {
+			if e.Lower == "" {
+				e.Lower = v
+			}
+		} else if e.Lower == "" {
+			// 列表从新到旧排列，最后一个高于约束的版本最接近
+			e.Higher = v
+		}
+	}
+
+	return e
+}
+
+// parseComparatorSet 解析以空格或逗号分隔的一组条件
+func parseComparatorSet(expr string) ([]comparator, error) {
+	switch strings.ToLower(expr) {
+	case "", "*", "x", "latest":
+		return []comparator{}, nil
+	}
+
+	// 连字符范围：1.20 - 1.22
+	if from, to, ok := strings.Cut(expr, " - "); ok {
+		lower, err := expandComparator(">=", strings.TrimSpace(from))
+		if err != nil {
+			return nil, err
+		}
+		upper, err := expandComparator("<=", strings.TrimSpace(to))
+		if err != nil {
+			return nil, err
+		}
+		return append(lower, upper...), nil
+	}
+
+	tokens := strings.Fields(strings.ReplaceAll(expr, ",", " "))
+
+	var set []comparator
+	for i := 0; i < len(tokens); i++ {
+		token := tokens[i]
+
+		op := ""
+		for _, candidate := range []string{"~=", "==", ">=", "<=", "!=", ">", "<", "=", "^", "~"} {
+			if strings.HasPrefix(token, candidate) {
+				op = candidate
+				break
+			}
+		}
+
+		version := strings.TrimPrefix(token, op)
+		// 允许运算符和版本号之间有空格，例如 ">= 1.21"
+		if version == "" && op != "" && i+1 < len(tokens) {
+			i++
+			version = tokens[i]
+		}
+
+		comparators, err := expandComparator(op, version)
+		if err != nil {
+			return nil, err
+		}
+		set = append(set, comparators...)
+	}
+
+	return set, nil
+}
+
+// expandComparator 将带运算符的部分版本号展开为基本比较条件
+func expandComparator(op, version string) ([]comparator, error) {
+	v, err := parsePartialVersion(version)
+	if err != nil {
+		return nil, err
+	}
+	nums := v.segments
+	exact := v.IsPrerelease() || len(v.build) > 0 || len(nums) >= 3
+
+	// 通配符版本，例如 * 或 x
+	if len(nums) == 0 {
+		switch op {
+		case "", "=", "==", ">=", "<=", "^", "~", "~=":
+			return []comparator{}, nil
+		default:
+			return nil, fmt.Errorf("运算符 %s 不能用于通配符版本", op)
+		}
+	}
+
+	// 部分版本的下一个版本，例如 1.22 -> 1.23
+	next := func(index int) Version {
+		bumped := append([]int{}, nums[:index+1]...)
+		bumped[index]++
+		return Version{segments: bumped}
+	}
+	lower := comparator{op: ">=", version: v}
+
+	switch op {
+	case "", "=", "==":
+		if exact {
+			return []comparator{{op: "=", version: v}}, nil
+		}
+		return []comparator{lower, {op: "<", version: next(len(nums) - 1)}}, nil
+
+	case "!=":
+		return []comparator{{op: "!=", version: v}}, nil
+
+	case ">=":
+		return []comparator{lower}, nil
+
+	case ">":
+		if exact {
+			return []comparator{{op: ">", version: v}}, nil
+		}
+		return []comparator{{op: ">=", version: next(len(nums) - 1)}}, nil
+
+	case "<":
+		return []comparator{{op: "<", version: v}}, nil
+
+	case "<=":
+		if exact {
+			return []comparator{{op: "<=", version: v}}, nil
+		}
+		return []comparator{{op: "<", version: next(len(nums) - 1)}}, nil
+
+	case "~":
+		// ~1.2.3 := >=1.2.3 <1.3.0，~1 := >=1.0.0 <2.0.0
+		index := 1
+		if len(nums) == 1 {
+			index = 0
+		}
+		return []comparator{lower, {op: "<", version: next(index)}}, nil
+
+	case "~=":
+		// PEP 440：~=3.11.4 := >=3.11.4 ==3.11.*，~=3.11 := >=3.11 ==3.*
+		if len(nums) < 2 {
+			return nil, fmt.Errorf("~= 至少需要两段版本号: %s", version)
+		}
+		return []comparator{lower, {op: "<", version: next(len(nums) - 2)}}, nil
+
+	case "^":
+		// ^1.2.3 := >=1.2.3 <2.0.0，^0.2.3 := >=0.2.3 <0.3.0，^0.0.3 := >=0.0.3 <0.0.4
+		index := 0
+		for index < len(nums)-1 && nums[index] == 0 {
+			index++
+		}
+		return []comparator{lower, {op: "<", version: next(index)}}, nil
+	}
+
+	return nil, fmt.Errorf("不支持的运算符: %s", op)
+}
+
+// parsePartialVersion 解析约束中的版本号，遇到通配符（x、X、*）时停止
+func parsePartialVersion(version string) (Version, error) {
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+	if version == "" {
+		return Version{}, fmt.Errorf("缺少版本号")
+	}
+
+	parts := strings.Split(version, ".")
+	for i, part := range parts {
+		if part != "x" && part != "X" && part != "*" {
+			continue
+		}
+
+		v := Version{original: version}
+		for _, prefix := range parts[:i] {
+			num, err := strconv.Atoi(prefix)
+			if err != nil {
+				return Version{}, fmt.Errorf("无效的版本号: %s", version)
+			}
+			v.segments = append(v.segments, num)
+		}
+		return v, nil
+	}
+
+	return ParseVersion(version)
+}
+
+// compare 比较版本与条件中的版本，条件未指定构建信息时忽略构建信息
+func (cmp comparator) compare(v Version) int {
+	if len(cmp.version.build) > 0 {
+		return v.Compare(cmp.version)
+	}
+	return v.comparePrecedence(cmp.version)
+}
+
+// setMatches 检查版本是否满足一组条件中的每一个
+func setMatches(set []comparator, v Version) bool {
+	for _, cmp := range set {
+		result := cmp.compare(v)
+
+		var ok bool
+		switch cmp.op {
+		case "=":
+			ok = result == 0
+		case "!=":
+			ok = result != 0
+		case ">":
+			ok = result > 0
+		case ">=":
+			ok = result >= 0
+		case "<":
+			// 上界不包含其自身的预发布版本，例如 <1.23 不匹配 1.23rc1
+			ok = result < 0 && !(v.IsPrerelease() && !cmp.version.IsPrerelease() && v.compareSegments(cmp.version) == 0)
+		case "<=":
+			ok = result <= 0
+		}
+
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// setAllowsPrerelease 检查一组条件是否明确允许该版本号的预发布版本
+func setAllowsPrerelease(set []comparator, v Version) bool {
+	for _, cmp := range set {
+		if cmp.version.IsPrerelease() && v.compareSegments(cmp.version) == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// isBelowSet 检查版本是否低于一组条件的下界
+func isBelowSet(set []comparator, v Version) bool {
+	for _, cmp := range set {
+		result := cmp.compare(v)
+		switch cmp.op {
+		case ">", ">=", "=":
+			if result < 0 || (cmp.op == ">" && result == 0) {
+				return true
+			}
+		}
+	}
+	return false
+}
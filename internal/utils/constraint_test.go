@@ -0,0 +1,116 @@
+package utils
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestParseConstraint(t *testing.T) {
+	tests := []struct {
+		constraint string
+		wantErr    bool
+	}{
+		{constraint: ""},
+		{constraint: "*"},
+		{constraint: "latest"},
+		{constraint: "18.x"},
+		{constraint: "^18.2"},
+		{constraint: "~3.11.4"},
+		{constraint: "~=3.11"},
+		{constraint: ">= 1.21, <1.23"},
+		{constraint: "1.20 - 1.22"},
+		{constraint: "^16 || ^18"},
+		{constraint: "~=3", wantErr: true},
+		{constraint: ">x", wantErr: true},
+		{constraint: "^abc", wantErr: true},
+		{constraint: "lts/*", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.constraint, func(t *testing.T) {
+			_, err := ParseConstraint(tt.constraint)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseConstraint(%q) 错误 = %v，期望返回错误: %v", tt.constraint, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMatchVersions(t *testing.T) {
+	node := []string{"16.20.2", "18.0.0", "18.2.0", "18.20.4", "19.0.0", "20.11.1", "21.0.0-rc.1"}
+	golang := []string{"1.20.14", "1.21.0", "1.21.13", "1.22rc1", "1.22.0", "1.22.3", "1.23rc2"}
+	python := []string{"3.11.3", "3.11.4", "3.11.9", "3.12.0b1", "3.12.0", "3.12.4", "3.13.0a4"}
+
+	tests := []struct {
+		name       string
+		constraint string
+		versions   []string
+		want       []string
+	}{
+		{"任意版本", "*", node, []string{"20.11.1", "19.0.0", "18.20.4", "18.2.0", "18.0.0", "16.20.2"}},
+		{"x", "x", python, []string{"3.12.4", "3.12.0", "3.11.9", "3.11.4", "3.11.3"}},
+		{"部分版本", "18", node, []string{"18.20.4", "18.2.0", "18.0.0"}},
+		{"通配符", "18.x", node, []string{"18.20.4", "18.2.0", "18.0.0"}},
+		{"精确版本", "18.2.0", node, []string{"18.2.0"}},
+		{"v前缀", "v18.2.0", node, []string{"18.2.0"}},
+		{"插入符", "^18.2", node, []string{"18.20.4", "18.2.0"}},
+		{"插入符 0.x", "^0.2.3", []string{"0.2.3", "0.2.9", "0.3.0"}, []string{"0.2.9", "0.2.3"}},
+		{"波浪号", "~3.11.4", python, []string{"3.11.9", "3.11.4"}},
+		{"波浪号主版本", "~1", golang, []string{"1.22.3", "1.22.0", "1.21.13", "1.21.0", "1.20.14"}},
+		{"PEP 440 兼容版本", "~=3.11.4", python, []string{"3.11.9", "3.11.4"}},
+		{"PEP 440 兼容次版本", "~=3.11", python, []string{"3.12.4", "3.12.0", "3.11.9", "3.11.4", "3.11.3"}},
+		{"范围", ">=1.21 <1.23", golang, []string{"1.22.3", "1.22.0", "1.21.13", "1.21.0"}},
+		{"逗号分隔", ">= 1.21, <1.22", golang, []string{"1.21.13", "1.21.0"}},
+		{"不等于", "3.12 != 3.12.0", python, []string{"3.12.4"}},
+		{"或", "^16 || ^20", node, []string{"20.11.1", "16.20.2"}},
+		{"连字符范围", "1.20 - 1.21", golang, []string{"1.21.13", "1.21.0", "1.20.14"}},
+		{"连字符范围精确上界", "18.2.0 - 19.0.0", node, []string{"19.0.0", "18.20.4", "18.2.0"}},
+		{"指定的预发布版本", "1.22rc1", golang, []string{"1.22rc1"}},
+		{"同版本号的预发布版本", ">=3.12.0b1", python, []string{"3.12.4", "3.12.0", "3.12.0b1"}},
+		{"上界不包含预发布版本", "<1.23", golang, []string{"1.22.3", "1.22.0", "1.21.13", "1.21.0", "1.20.14"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MatchVersions(tt.constraint, tt.versions)
+			if err != nil {
+				t.Fatalf("MatchVersions(%q) 失败: %v", tt.constraint, err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("MatchVersions(%q) = %v，期望 %v", tt.constraint, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchVersionsNoMatch(t *testing.T) {
+	tests := []struct {
+		name        string
+		constraint  string
+		versions    []string
+		lower       string
+		higher      string
+		prereleases []string
+	}{
+		{name: "空列表", constraint: "18", versions: nil},
+		{name: "介于两个版本之间", constraint: "17", versions: []string{"16.20.2", "18.0.0"}, lower: "16.20.2", higher: "18.0.0"},
+		{name: "高于所有版本", constraint: ">=22", versions: []string{"18.0.0", "20.11.1"}, lower: "20.11.1"},
+		{name: "仅有预发布版本", constraint: ">=1.22.5", versions: []string{"1.22.3", "1.23rc2"}, lower: "1.22.3", prereleases: []string{"1.23rc2"}},
+		{name: "Java 完整版本", constraint: "17.0.9", versions: []string{"11", "17", "21"}, lower: "17", higher: "21"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := MatchVersions(tt.constraint, tt.versions)
+			var noMatch *NoMatchError
+			if !errors.As(err, &noMatch) {
+				t.Fatalf("期望 *NoMatchError，实际为 %v", err)
+			}
+			if noMatch.Available != len(tt.versions) || noMatch.Lower != tt.lower || noMatch.Higher != tt.higher ||
+				!slices.Equal(noMatch.Prereleases, tt.prereleases) {
+				t.Errorf("NoMatchError = %+v，期望 Lower=%q Higher=%q Prereleases=%v", noMatch, tt.lower, tt.higher, tt.prereleases)
+			}
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"strings"
 )
 
 // IsDirEntry 判断是否是目录
@@ -28,7 +29,16 @@ func CheckDirExists(dirPath string) (bool, error) {
 	return info.IsDir(), nil
 }
 
-// CopyFile 复制文件
+// IsWithin 判断 path 是否是 root 或位于 root 中
+func IsWithin(root, path string) bool {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return false
+	}
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) && !filepath.IsAbs(rel)
+}
+
+// CopyFile 复制文件，保留权限和修改时间
 func CopyFile(src, dst string) error {
 	srcFile, err := os.Open(src)
 	if err != nil {
@@ -36,18 +46,38 @@ func CopyFile(src, dst string) error {
 	}
 	defer srcFile.Close()
 
-	dstFile, err := os.Create(dst)
+	info, err := srcFile.Stat()
 	if err != nil {
 		return err
 	}
-	defer dstFile.Close()
 
-	_, err = io.Copy(dstFile, srcFile)
-	return err
+	dstFile, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(dstFile, srcFile); err != nil {
+		dstFile.Close()
+		return err
+	}
+	if err := dstFile.Close(); err != nil {
+		return err
+	}
+
+	// 目标文件已存在时 OpenFile 不会修改权限
+	if err := os.Chmod(dst, info.Mode().Perm()); err != nil {
+		return err
+	}
+	return os.Chtimes(dst, info.ModTime(), info.ModTime())
 }
 
-// CopyDir 复制目录
+// CopyDir 复制目录，保留权限、修改时间和符号链接。
+// 无法创建符号链接时（例如Windows上没有权限）复制链接指向的内容
 func CopyDir(src, dst string) error {
+	info, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+
 	// 创建目标目录
 	if err := os.MkdirAll(dst, 0755); err != nil {
 		return err
@@ -63,12 +93,17 @@ func CopyDir(src, dst string) error {
 		srcPath := filepath.Join(src, entry.Name())
 		dstPath := filepath.Join(dst, entry.Name())
 
-		if entry.IsDir() {
+		switch {
+		case entry.Type()&os.ModeSymlink != 0:
+			if err := copySymlink(srcPath, dstPath); err != nil {
+				return err
+			}
+		case entry.IsDir():
 			// 递归复制子目录
 			if err := CopyDir(srcPath, dstPath); err != nil {
 				return err
 			}
-		} else {
+		default:
 			// 复制文件
 			if err := CopyFile(srcPath, dstPath); err != nil {
 				return err
@@ -76,5 +111,32 @@ func CopyDir(src, dst string) error {
 		}
 	}
 
-	return nil
+	// 复制内容会改变修改时间，最后设置目录的权限和修改时间，与解压时一样保留所有者的权限
+	if err := os.Chmod(dst, info.Mode().Perm()|0700); err != nil {
+		return err
+	}
+	return os.Chtimes(dst, info.ModTime(), info.ModTime())
+}
+
+// copySymlink 复制符号链接，无法创建时复制链接指向的文件或目录
+func copySymlink(src, dst string) error {
+	target, err := os.Readlink(src)
+	if err != nil {
+		return err
+	}
+	if err := os.RemoveAll(dst); err != nil {
+		return err
+	}
+	if err := os.Symlink(target, dst); err == nil {
+		return nil
+	}
+
+	info, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+	if info.IsDir() {
+		return CopyDir(src, dst)
+	}
+	return CopyFile(src, dst)
 }
@@ -1,52 +1,212 @@
 package utils
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
 	"path/filepath"
+	"strings"
 )
 
+// PartSuffix 未完成下载的临时文件后缀
+const PartSuffix = ".part"
+
+// partMeta 记录未完成下载的来源信息，续传前用于确认服务器上的文件没有变化
+type partMeta struct {
+	URL          string `json:"url"`
+	ETag         string `json:"etag"`
+	LastModified string `json:"last_modified"`
+	Size         int64  `json:"size"` // 文件总大小，未知时为0
+}
+
+// PartFilePath 返回目标文件对应的未完成下载文件路径
+func PartFilePath(destPath string) string {
+	return destPath + PartSuffix
+}
+
+// partMetaPath 返回未完成下载的信息文件路径
+func partMetaPath(partPath string) string {
+	return partPath + ".meta"
+}
+
 // DownloadFile 下载文件到指定路径
+// 下载过程中写入 .part 文件，中断后再次调用会从断点继续，完成后才移动到目标路径
 func DownloadFile(url string, destPath string) error {
+	partPath := PartFilePath(destPath)
+	if err := ResumeDownload(url, partPath); err != nil {
+		return err
+	}
+	return PromotePartFile(partPath, destPath)
+}
+
+// ResumeDownload 下载文件到 .part 文件，已有未完成的下载时使用Range请求继续下载
+// 续传时通过 If-Range 携带上次记录的 ETag 或 Last-Modified，服务器上的文件已变化时重新下载
+// 下载失败时保留已下载的部分，供下次继续
+func ResumeDownload(url, partPath string) error {
 	// 创建目标目录
-	if err := os.MkdirAll(filepath.Dir(destPath), 0755); err != nil {
+	if err := os.MkdirAll(filepath.Dir(partPath), 0755); err != nil {
 		return fmt.Errorf("创建目录失败: %w", err)
 	}
 
-	// 发起HTTP GET请求
-	resp, err := http.Get(url)
+	// 检查是否可以续传
+	meta, offset := resumableOffset(url, partPath)
+	if offset > 0 && offset == meta.Size {
+		Log.Info("文件已下载完成，无需继续下载")
+		return nil
+	}
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("创建请求失败: %w", err)
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+		req.Header.Set("If-Range", meta.validator())
+		Log.Download(fmt.Sprintf("从 %d 字节处继续下载", offset))
+	}
+
+	resp, err := DoRequest(req)
 	if err != nil {
 		return fmt.Errorf("下载失败: %w", err)
 	}
 	defer resp.Body.Close()
 
-	// 检查响应状态码
-	if resp.StatusCode != http.StatusOK {
+	var out *os.File
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+		if offset == 0 || !strings.HasPrefix(resp.Header.Get("Content-Range"), fmt.Sprintf("bytes %d-", offset)) {
+			RemovePartFile(partPath)
+			return fmt.Errorf("下载失败: 服务器返回了无效的范围 %q", resp.Header.Get("Content-Range"))
+		}
+		out, err = os.OpenFile(partPath, os.O_WRONLY|os.O_APPEND, 0644)
+
+	case http.StatusOK:
+		if offset > 0 {
+			Log.Info("服务器上的文件已变化或不支持断点续传，重新下载")
+		}
+		offset = 0
+		meta = partMeta{
+			URL:          url,
+			ETag:         resp.Header.Get("ETag"),
+			LastModified: resp.Header.Get("Last-Modified"),
+			Size:         max(resp.ContentLength, 0),
+		}
+		if err := writePartMeta(partPath, meta); err != nil {
+			return err
+		}
+		out, err = os.Create(partPath)
+
+	case http.StatusRequestedRangeNotSatisfiable:
+		// 已下载的部分与服务器上的文件不一致，丢弃后下次重新下载
+		RemovePartFile(partPath)
+		return fmt.Errorf("下载失败: 已下载的部分与服务器上的文件不一致，请重试")
+
+	default:
 		return fmt.Errorf("下载失败: HTTP %d", resp.StatusCode)
 	}
-
-	// 直接写入目标文件
-	out, err := os.Create(destPath)
 	if err != nil {
 		return fmt.Errorf("创建文件失败: %w", err)
 	}
 	defer out.Close()
 
-	// 写入文件
-	_, err = io.Copy(out, resp.Body)
+	// 显示下载进度
+	total := int64(-1)
+	if meta.Size > 0 {
+		total = meta.Size
+	}
+	progress := NewProgress("下载 "+strings.TrimSuffix(filepath.Base(partPath), PartSuffix), total)
+	progress.SetInitial(offset)
+
+	// 写入文件，中断时保留已写入的部分
+	written, err := io.Copy(io.MultiWriter(out, progress), resp.Body)
+	progress.Finish()
 	if err != nil {
-		os.Remove(destPath)
-		return fmt.Errorf("写入文件失败: %w", err)
+		return fmt.Errorf("下载中断（已下载 %d 字节，重试时将继续下载）: %w", offset+written, err)
+	}
+
+	if meta.Size > 0 && offset+written != meta.Size {
+		return fmt.Errorf("下载不完整: 期望 %d 字节，实际 %d 字节，重试时将继续下载", meta.Size, offset+written)
 	}
 
 	return nil
 }
 
+// PromotePartFile 将下载完成的 .part 文件移动到目标路径，并删除续传信息
+func PromotePartFile(partPath, destPath string) error {
+	if err := os.Rename(partPath, destPath); err != nil {
+		return fmt.Errorf("移动下载文件失败: %w", err)
+	}
+	os.Remove(partMetaPath(partPath))
+	return nil
+}
+
+// RemovePartFile 删除未完成的下载文件及其续传信息
+func RemovePartFile(partPath string) {
+	os.Remove(partPath)
+	os.Remove(partMetaPath(partPath))
+}
+
+// resumableOffset 返回可以续传的起始位置，无法续传时返回0
+func resumableOffset(url, partPath string) (partMeta, int64) {
+	info, err := os.Stat(partPath)
+	if err != nil || info.Size() == 0 {
+		return partMeta{}, 0
+	}
+
+	data, err := os.ReadFile(partMetaPath(partPath))
+	if err != nil {
+		return partMeta{}, 0
+	}
+
+	var meta partMeta
+	if err := json.Unmarshal(data, &meta); err != nil || meta.URL != url || meta.validator() == "" {
+		return partMeta{}, 0
+	}
+
+	if meta.Size > 0 && info.Size() > meta.Size {
+		return partMeta{}, 0
+	}
+	return meta, info.Size()
+}
+
+// validator 返回 If-Range 使用的校验器，弱ETag不能用于范围请求
+func (m partMeta) validator() string {
+	if m.ETag != "" && !strings.HasPrefix(m.ETag, "W/") {
+		return m.ETag
+	}
+	return m.LastModified
+}
+
+// writePartMeta 保存续传信息
+func writePartMeta(partPath string, meta partMeta) error {
+	data, err := json.Marshal(meta)
+	if err != nil {
+		return fmt.Errorf("保存续传信息失败: %w", err)
+	}
+	if err := os.WriteFile(partMetaPath(partPath), data, 0644); err != nil {
+		return fmt.Errorf("保存续传信息失败: %w", err)
+	}
+	return nil
+}
+
 // FetchJSON 发起HTTP GET请求并返回响应内容
 func FetchJSON(url string) ([]byte, error) {
-	resp, err := http.Get(url)
+	return FetchBytes(url)
+}
+
+// FetchBytes 发起HTTP GET请求并原样返回响应内容，用于校验文件、签名、公钥等非JSON内容
+func FetchBytes(url string) ([]byte, error) {
+	ctx, cancel := requestContext()
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("创建请求失败: %w", err)
+	}
+
+	resp, err := DoRequest(req)
 	if err != nil {
 		return nil, fmt.Errorf("HTTP请求失败: %w", err)
 	}
@@ -62,4 +222,4 @@ func FetchJSON(url string) ([]byte, error) {
 	}
 
 	return body, nil
-} 
\ No newline at end of file
+}
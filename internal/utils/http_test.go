@@ -0,0 +1,94 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestResumableOffset(t *testing.T) {
+	const url = "https://nodejs.org/dist/v20.11.1/node-v20.11.1-linux-x64.tar.xz"
+
+	tests := []struct {
+		name       string
+		part       string // 未完成下载的内容，为空时不创建
+		meta       string // 续传信息，为空时不创建
+		wantOffset int64
+	}{
+		{
+			name:       "ETag",
+			part:       "12345",
+			meta:       `{"url":"` + url + `","etag":"\"abc\"","size":10}`,
+			wantOffset: 5,
+		},
+		{
+			name:       "Last-Modified",
+			part:       "12345",
+			meta:       `{"url":"` + url + `","last_modified":"Tue, 13 Feb 2024 00:00:00 GMT"}`,
+			wantOffset: 5,
+		},
+		{
+			name:       "弱ETag时使用Last-Modified",
+			part:       "12345",
+			meta:       `{"url":"` + url + `","etag":"W/\"abc\"","last_modified":"Tue, 13 Feb 2024 00:00:00 GMT"}`,
+			wantOffset: 5,
+		},
+		{
+			name: "只有弱ETag",
+			part: "12345",
+			meta: `{"url":"` + url + `","etag":"W/\"abc\""}`,
+		},
+		{
+			name: "没有校验器",
+			part: "12345",
+			meta: `{"url":"` + url + `","size":10}`,
+		},
+		{
+			name: "地址不同",
+			part: "12345",
+			meta: `{"url":"https://npmmirror.com/mirrors/node/v20.11.1/node-v20.11.1-linux-x64.tar.xz","etag":"\"abc\""}`,
+		},
+		{
+			name: "超过文件总大小",
+			part: "12345",
+			meta: `{"url":"` + url + `","etag":"\"abc\"","size":4}`,
+		},
+		{
+			name: "续传信息损坏",
+			part: "12345",
+			meta: `{"url":`,
+		},
+		{
+			name: "没有续传信息",
+			part: "12345",
+		},
+		{
+			name: "没有未完成的下载",
+			meta: `{"url":"` + url + `","etag":"\"abc\""}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			partPath := PartFilePath(filepath.Join(t.TempDir(), "node.tar.xz"))
+			if tt.part != "" {
+				if err := os.WriteFile(partPath, []byte(tt.part), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if tt.meta != "" {
+				if err := os.WriteFile(partMetaPath(partPath), []byte(tt.meta), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			meta, offset := resumableOffset(url, partPath)
+			if offset != tt.wantOffset {
+				t.Errorf("resumableOffset() = %d，期望 %d", offset, tt.wantOffset)
+			}
+			if offset > 0 && meta.validator() == "" {
+				t.Errorf("可以续传时缺少校验器: %+v", meta)
+			}
+		})
+	}
+}
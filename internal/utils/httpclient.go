@@ -0,0 +1,173 @@
+package utils
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"math/rand"
+	"net"
+	"net/http"
+	"os"
+	"runtime"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// 网络请求的默认配置
+const (
+	DefaultRequestTimeout = 60 * time.Second // 获取元数据等一次性请求的超时
+	DefaultRetries        = 3                // 5xx/429 的重试次数
+	maxRetryDelay         = 30 * time.Second
+)
+
+// HTTPOptions 网络请求的配置
+type HTTPOptions struct {
+	CACertFiles    []string      // 额外信任的根证书文件（PEM格式），例如企业代理的证书
+	RequestTimeout time.Duration // 一次性请求的超时，0 使用默认值；下载文件只限制连接和等待响应头的时间
+	Retries        int           // 服务器返回 5xx 或 429 时的重试次数，负数表示不重试
+	UserAgent      string        // User-Agent，为空时使用默认值
+}
+
+var (
+	httpMu      sync.RWMutex
+	httpClient  = &http.Client{Transport: newTransport()} // 不设置整体超时以免中断大文件下载
+	httpOptions = HTTPOptions{RequestTimeout: DefaultRequestTimeout, Retries: DefaultRetries}
+)
+
+// DefaultUserAgent 返回默认的User-Agent
+func DefaultUserAgent() string {
+	return fmt.Sprintf("svm (%s/%s; +https://github.com/Eusen/svm)", runtime.GOOS, runtime.GOARCH)
+}
+
+// ConfigureHTTP 配置所有网络请求共用的客户端
+// 代理始终从 HTTPS_PROXY、HTTP_PROXY、NO_PROXY 环境变量读取
+func ConfigureHTTP(opts HTTPOptions) error {
+	transport := newTransport()
+	if len(opts.CACertFiles) > 0 {
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+
+		for _, file := range opts.CACertFiles {
+			data, err := os.ReadFile(file)
+			if err != nil {
+				return fmt.Errorf("读取根证书失败: %w", err)
+			}
+			if !pool.AppendCertsFromPEM(data) {
+				return fmt.Errorf("根证书 %s 中没有有效的PEM证书", file)
+			}
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool}
+	}
+
+	if opts.RequestTimeout <= 0 {
+		opts.RequestTimeout = DefaultRequestTimeout
+	}
+	if opts.UserAgent == "" {
+		opts.UserAgent = DefaultUserAgent()
+	}
+
+	httpMu.Lock()
+	defer httpMu.Unlock()
+	httpClient = &http.Client{Transport: transport}
+	httpOptions = opts
+	return nil
+}
+
+// newTransport 创建带有连接超时和代理设置的传输层
+func newTransport() *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = http.ProxyFromEnvironment
+	transport.DialContext = (&net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+	}).DialContext
+	transport.TLSHandshakeTimeout = 30 * time.Second
+	transport.ResponseHeaderTimeout = 60 * time.Second
+	return transport
+}
+
+// currentHTTP 返回当前的客户端及配置
+func currentHTTP() (*http.Client, HTTPOptions) {
+	httpMu.RLock()
+	defer httpMu.RUnlock()
+	return httpClient, httpOptions
+}
+
+// DoRequest 使用共用的客户端发送请求
+// 服务器返回 5xx 或 429 时按指数退避重试（优先使用 Retry-After），请求必须没有请求体
+// 离线模式下直接返回 ErrOffline
+func DoRequest(req *http.Request) (*http.Response, error) {
+	if IsOffline() {
+		return nil, fmt.Errorf("%w: %s", ErrOffline, req.URL.Redacted())
+	}
+
+	client, opts := currentHTTP()
+
+	if req.Header.Get("User-Agent") == "" {
+		userAgent := opts.UserAgent
+		if userAgent == "" {
+			userAgent = DefaultUserAgent()
+		}
+		req.Header.Set("User-Agent", userAgent)
+	}
+
+	for attempt := 0; ; attempt++ {
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+
+		if !shouldRetry(resp.StatusCode) || attempt >= opts.Retries {
+			return resp, nil
+		}
+		resp.Body.Close()
+
+		delay := retryDelay(attempt, resp.Header.Get("Retry-After"))
+		Log.Warning(fmt.Sprintf("%s 返回 HTTP %d，%s 后重试（%d/%d）",
+			req.URL.Redacted(), resp.StatusCode, delay.Round(time.Millisecond), attempt+1, opts.Retries))
+
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+// HTTPGet 发送GET请求，不限制整体时间，用于下载文件
+func HTTPGet(url string) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return DoRequest(req)
+}
+
+// requestContext 返回一次性请求使用的带超时的上下文
+func requestContext() (context.Context, context.CancelFunc) {
+	_, opts := currentHTTP()
+	return context.WithTimeout(context.Background(), opts.RequestTimeout)
+}
+
+// shouldRetry 判断状态码是否值得重试
+func shouldRetry(statusCode int) bool {
+	return statusCode == http.StatusTooManyRequests || statusCode >= 500
+}
+
+// retryDelay 计算第 attempt 次重试前的等待时间：1s、2s、4s……加上随机抖动，最长30秒
+func retryDelay(attempt int, retryAfter string) time.Duration {
+	if seconds, err := strconv.Atoi(retryAfter); err == nil && seconds >= 0 {
+		return min(time.Duration(seconds)*time.Second, maxRetryDelay)
+	}
+	if when, err := http.ParseTime(retryAfter); err == nil {
+		return min(max(time.Until(when), 0), maxRetryDelay)
+	}
+
+	delay := time.Second << min(attempt, 5)
+	delay += time.Duration(rand.Int63n(int64(delay / 2)))
+	return min(delay, maxRetryDelay)
+}
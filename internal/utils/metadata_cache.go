@@ -0,0 +1,207 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultMetadataTTL 元数据缓存的默认有效期
+const DefaultMetadataTTL = 6 * time.Hour
+
+// ErrOffline 表示离线模式下需要访问网络
+var ErrOffline = errors.New("离线模式下不能访问网络")
+
+var offline atomic.Bool
+
+// SetOffline 设置离线模式，离线模式下所有网络请求都会失败，元数据只从缓存读取
+func SetOffline(enabled bool) {
+	offline.Store(enabled)
+}
+
+// IsOffline 判断是否处于离线模式
+func IsOffline() bool {
+	return offline.Load()
+}
+
+// MetadataCache 缓存远程元数据（版本索引等），过期后使用 ETag / Last-Modified 进行条件请求
+type MetadataCache struct {
+	Dir string        // 缓存目录
+	TTL time.Duration // 有效期，有效期内直接使用缓存，0 表示每次都重新验证
+}
+
+// metadataEntry 缓存条目的信息
+type metadataEntry struct {
+	Key          string    `json:"key"`
+	URL          string    `json:"url"` // 实际获取内容的地址（可能是镜像）
+	ETag         string    `json:"etag"`
+	LastModified string    `json:"last_modified"`
+	FetchedAt    time.Time `json:"fetched_at"`
+}
+
+// Fetch 获取元数据，key 为缓存键（通常是官方地址），urls 为按顺序尝试的地址
+// 缓存未过期时直接返回缓存；过期后依次向各地址发起条件请求，全部失败时退回使用过期的缓存
+// 离线模式下只使用缓存，没有缓存时返回 ErrOffline
+func (c *MetadataCache) Fetch(key string, urls []string) ([]byte, error) {
+	dataPath, entryPath := c.paths(key)
+	entry, cached := c.load(dataPath, entryPath)
+
+	if IsOffline() {
+		if !cached {
+			return nil, fmt.Errorf("%w: 没有 %s 的缓存", ErrOffline, key)
+		}
+		return os.ReadFile(dataPath)
+	}
+
+	if cached && time.Since(entry.FetchedAt) < c.TTL {
+		return os.ReadFile(dataPath)
+	}
+
+	var lastErr error
+	for i, url := range urls {
+		// 缓存的校验器只对同一地址有效
+		var etag, lastModified string
+		if cached && entry.URL == url {
+			etag, lastModified = entry.ETag, entry.LastModified
+		}
+
+		resp, err := fetchConditional(url, etag, lastModified)
+		if err != nil {
+			lastErr = err
+			if i < len(urls)-1 {
+				Log.Warning(fmt.Sprintf("从 %s 获取失败: %v，尝试下一个镜像", url, err))
+			}
+			continue
+		}
+
+		entry.FetchedAt = time.Now()
+		if resp.notModified {
+			// 内容未变化，只更新获取时间
+			if err := c.saveEntry(entryPath, entry); err != nil {
+				Log.Warning(fmt.Sprintf("更新元数据缓存失败: %v", err))
+			}
+			return os.ReadFile(dataPath)
+		}
+
+		entry = metadataEntry{
+			Key:          key,
+			URL:          url,
+			ETag:         resp.etag,
+			LastModified: resp.lastModified,
+			FetchedAt:    entry.FetchedAt,
+		}
+		if err := c.save(dataPath, entryPath, entry, resp.body); err != nil {
+			Log.Warning(fmt.Sprintf("保存元数据缓存失败: %v", err))
+		}
+		return resp.body, nil
+	}
+
+	if cached {
+		Log.Warning(fmt.Sprintf("获取 %s 失败，使用 %s 的缓存: %v", key, entry.FetchedAt.Format("2006-01-02 15:04"), lastErr))
+		return os.ReadFile(dataPath)
+	}
+	return nil, lastErr
+}
+
+// paths 返回缓存内容及其信息的文件路径
+func (c *MetadataCache) paths(key string) (string, string) {
+	sum := sha256.Sum256([]byte(key))
+	name := hex.EncodeToString(sum[:8])
+	return filepath.Join(c.Dir, name+".data"), filepath.Join(c.Dir, name+".json")
+}
+
+// load 读取缓存条目，内容文件不存在时视为没有缓存
+func (c *MetadataCache) load(dataPath, entryPath string) (metadataEntry, bool) {
+	var entry metadataEntry
+	data, err := os.ReadFile(entryPath)
+	if err != nil || json.Unmarshal(data, &entry) != nil {
+		return metadataEntry{}, false
+	}
+	if _, err := os.Stat(dataPath); err != nil {
+		return metadataEntry{}, false
+	}
+	return entry, true
+}
+
+// save 保存缓存内容及其信息，先写临时文件再重命名，避免留下不完整的缓存
+func (c *MetadataCache) save(dataPath, entryPath string, entry metadataEntry, body []byte) error {
+	if err := os.MkdirAll(c.Dir, 0755); err != nil {
+		return err
+	}
+
+	tmpPath := dataPath + ".tmp"
+	if err := os.WriteFile(tmpPath, body, 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, dataPath); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	return c.saveEntry(entryPath, entry)
+}
+
+// saveEntry 保存缓存条目信息
+func (c *MetadataCache) saveEntry(entryPath string, entry metadataEntry) error {
+	data, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(entryPath, data, 0644)
+}
+
+// conditionalResponse 条件请求的结果
+type conditionalResponse struct {
+	body         []byte
+	etag         string
+	lastModified string
+	notModified  bool
+}
+
+// fetchConditional 发起条件GET请求，服务器返回304时 notModified 为 true
+func fetchConditional(url, etag, lastModified string) (conditionalResponse, error) {
+	ctx, cancel := requestContext()
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return conditionalResponse{}, fmt.Errorf("创建请求失败: %w", err)
+	}
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+	if lastModified != "" {
+		req.Header.Set("If-Modified-Since", lastModified)
+	}
+
+	resp, err := DoRequest(req)
+	if err != nil {
+		return conditionalResponse{}, fmt.Errorf("HTTP请求失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified {
+		return conditionalResponse{notModified: true}, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return conditionalResponse{}, fmt.Errorf("HTTP请求失败: %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return conditionalResponse{}, fmt.Errorf("读取响应失败: %w", err)
+	}
+
+	return conditionalResponse{
+		body:         body,
+		etag:         resp.Header.Get("ETag"),
+		lastModified: resp.Header.Get("Last-Modified"),
+	}, nil
+}
@@ -0,0 +1,157 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/schollz/progressbar/v3"
+	"golang.org/x/term"
+)
+
+// progressLogInterval 非终端环境下输出进度日志的间隔
+const progressLogInterval = 5 * time.Second
+
+// Progress 显示下载或解压进度
+// 标准输出是终端时显示进度条（大小、速度、剩余时间），否则定期输出普通日志，保证CI日志可读
+type Progress struct {
+	bar         *progressbar.ProgressBar
+	description string
+	total       int64 // 总字节数，未知时为-1
+	current     int64
+	initial     int64 // 续传时已有的字节数，不计入速度
+	start       time.Time
+	lastLog     time.Time
+}
+
+// NewProgress 创建进度显示，total 未知时传入-1
+func NewProgress(description string, total int64) *Progress {
+	p := &Progress{
+		description: description,
+		total:       total,
+		start:       time.Now(),
+	}
+	p.lastLog = p.start
+
+	if term.IsTerminal(int(os.Stdout.Fd())) {
+		p.bar = progressbar.NewOptions64(
+			total,
+			progressbar.OptionSetDescription(description),
+			progressbar.OptionSetWriter(os.Stdout),
+			progressbar.OptionShowBytes(true),
+			progressbar.OptionShowTotalBytes(true),
+			progressbar.OptionSetWidth(30),
+			progressbar.OptionThrottle(100*time.Millisecond),
+			progressbar.OptionShowCount(),
+			progressbar.OptionSetPredictTime(true),
+			progressbar.OptionSpinnerType(14),
+			progressbar.OptionOnCompletion(func() {
+				fmt.Fprintln(os.Stdout)
+			}),
+		)
+	}
+	return p
+}
+
+// SetInitial 设置已完成的字节数，例如断点续传时已下载的部分
+func (p *Progress) SetInitial(n int64) {
+	p.current = n
+	p.initial = n
+	if p.bar != nil {
+		p.bar.Set64(n)
+	}
+}
+
+// Write 实现 io.Writer，记录写入的字节数，可与 io.TeeReader 或 io.MultiWriter 配合使用
+func (p *Progress) Write(b []byte) (int, error) {
+	p.Add(int64(len(b)))
+	return len(b), nil
+}
+
+// Add 增加已完成的字节数
+func (p *Progress) Add(n int64) {
+	p.current += n
+	if p.bar != nil {
+		p.bar.Add64(n)
+		return
+	}
+
+	if time.Since(p.lastLog) >= progressLogInterval {
+		p.lastLog = time.Now()
+		Log.Info(p.status())
+	}
+}
+
+// Finish 结束进度显示
+func (p *Progress) Finish() {
+	if p.bar != nil {
+		if p.total > 0 && p.current >= p.total {
+			p.bar.Finish()
+		} else {
+			// 未完成（出错中断）或长度未知时只换行，不把进度条补满
+			p.bar.Exit()
+			fmt.Fprintln(os.Stdout)
+		}
+		return
+	}
+
+	// 耗时很短的操作不输出完成日志
+	if time.Since(p.start) >= progressLogInterval {
+		Log.Info(p.status())
+	}
+}
+
+// status 返回进度描述，例如 "下载 node-v20.zip: 12.3 MB / 30.1 MB (40%)，2.1 MB/s，剩余 8s"
+func (p *Progress) status() string {
+	elapsed := time.Since(p.start)
+	speed := float64(p.current-p.initial) / max(elapsed.Seconds(), 0.001)
+
+	if p.total <= 0 {
+		return fmt.Sprintf("%s: %s，%s/s", p.description, FormatBytes(p.current), FormatBytes(int64(speed)))
+	}
+
+	msg := fmt.Sprintf("%s: %s / %s (%d%%)，%s/s", p.description,
+		FormatBytes(p.current), FormatBytes(p.total), p.current*100/p.total, FormatBytes(int64(speed)))
+	if speed > 0 && p.current < p.total {
+		remaining := time.Duration(float64(p.total-p.current) / speed * float64(time.Second))
+		msg += fmt.Sprintf("，剩余 %s", remaining.Round(time.Second))
+	}
+	return msg
+}
+
+// FormatBytes 将字节数格式化为易读的形式，例如 1.5 MB
+func FormatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+
+	div, exp := int64(unit), 0
+	for value := n / unit; value >= unit; value /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// ParseBytes 解析易读的大小，例如 5G、500MB、1.5GiB、1024，单位按1024换算
+func ParseBytes(s string) (int64, error) {
+	value := strings.ToUpper(strings.TrimSpace(s))
+	value = strings.TrimSuffix(strings.TrimSuffix(value, "B"), "I")
+
+	multiplier := int64(1)
+	if n := len(value); n > 0 {
+		if exp := strings.IndexByte("KMGTPE", value[n-1]); exp >= 0 {
+			multiplier = int64(1) << (10 * (exp + 1))
+			value = strings.TrimSpace(value[:n-1])
+		}
+	}
+
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil || number < 0 {
+		return 0, fmt.Errorf("无效的大小: %q", s)
+	}
+	return int64(number * float64(multiplier)), nil
+}
@@ -0,0 +1,136 @@
+package utils
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/ProtonMail/go-crypto/openpgp/clearsign"
+)
+
+// armorBegin ASCII armor块的起始标记
+var armorBegin = []byte("-----BEGIN PGP")
+
+// SignatureError 表示校验文件的OpenPGP签名缺失或无法通过验证
+type SignatureError struct {
+	URL    string // 被签名文件的地址
+	Reason string // 失败原因
+	Err    error  // 底层错误
+}
+
+func (e *SignatureError) Error() string {
+	msg := fmt.Sprintf("%s 的签名验证失败: %s", e.URL, e.Reason)
+	if e.Err != nil {
+		msg += fmt.Sprintf(": %v", e.Err)
+	}
+	return msg
+}
+
+func (e *SignatureError) Unwrap() error {
+	return e.Err
+}
+
+// LoadKeyring 读取OpenPGP公钥环文件
+// 支持二进制格式（如gpg导出的.gpg文件）以及一个或多个拼接在一起的ASCII armor公钥块（.asc）
+func LoadKeyring(path string) (openpgp.EntityList, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取公钥环失败: %w", err)
+	}
+
+	keyring, err := ParseKeyring(data)
+	if err != nil {
+		return nil, fmt.Errorf("解析公钥环 %s 失败: %w", path, err)
+	}
+	return keyring, nil
+}
+
+// ParseKeyring 解析OpenPGP公钥环内容
+func ParseKeyring(data []byte) (openpgp.EntityList, error) {
+	if !bytes.Contains(data, armorBegin) {
+		keyring, err := openpgp.ReadKeyRing(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		return keyring, nil
+	}
+
+	// 逐个解析armor块，keys目录中的公钥通常是直接拼接在一起的
+	var keyring openpgp.EntityList
+	for _, chunk := range splitArmorBlocks(data) {
+		block, err := armor.Decode(bytes.NewReader(chunk))
+		if err != nil {
+			return nil, err
+		}
+		if block.Type != openpgp.PublicKeyType {
+			continue
+		}
+
+		entities, err := openpgp.ReadKeyRing(block.Body)
+		if err != nil {
+			return nil, err
+		}
+		keyring = append(keyring, entities...)
+	}
+
+	if len(keyring) == 0 {
+		return nil, fmt.Errorf("没有找到公钥")
+	}
+	return keyring, nil
+}
+
+// splitArmorBlocks 按起始标记拆分多个armor块
+func splitArmorBlocks(data []byte) [][]byte {
+	var chunks [][]byte
+	for {
+		start := bytes.Index(data, armorBegin)
+		if start < 0 {
+			return chunks
+		}
+		data = data[start:]
+
+		next := bytes.Index(data[len(armorBegin):], armorBegin)
+		if next < 0 {
+			return append(chunks, data)
+		}
+		chunks = append(chunks, data[:next+len(armorBegin)])
+		data = data[next+len(armorBegin):]
+	}
+}
+
+// VerifyDetachedSignature 使用公钥环验证分离签名（二进制或ASCII armor格式），返回签名者
+func VerifyDetachedSignature(keyring openpgp.EntityList, data, signature []byte) (*openpgp.Entity, error) {
+	if bytes.Contains(signature, armorBegin) {
+		return openpgp.CheckArmoredDetachedSignature(keyring, bytes.NewReader(data), bytes.NewReader(signature), nil)
+	}
+	return openpgp.CheckDetachedSignature(keyring, bytes.NewReader(data), bytes.NewReader(signature), nil)
+}
+
+// VerifyClearsigned 验证明文签名的文件（如Node.js的SHASUMS256.txt.asc），返回签名的原文及签名者
+func VerifyClearsigned(keyring openpgp.EntityList, signed []byte) ([]byte, *openpgp.Entity, error) {
+	block, _ := clearsign.Decode(signed)
+	if block == nil {
+		return nil, nil, fmt.Errorf("不是有效的明文签名文件")
+	}
+
+	signer, err := block.VerifySignature(keyring, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	return block.Plaintext, signer, nil
+}
+
+// SignerName 返回签名者的身份描述，例如 "Jane Doe <jane@example.com> (C0D6248439F1D5604AAFFB4021D900FFDB233756)"
+func SignerName(signer *openpgp.Entity) string {
+	if signer == nil || signer.PrimaryKey == nil {
+		return "未知"
+	}
+
+	fingerprint := fmt.Sprintf("%X", signer.PrimaryKey.Fingerprint)
+	for name := range signer.Identities {
+		return fmt.Sprintf("%s (%s)", name, fingerprint)
+	}
+	return fingerprint
+}
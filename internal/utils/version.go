@@ -1,124 +1,369 @@
 package utils
 
 import (
+	"fmt"
 	"sort"
-	"strconv"
 	"strings"
 )
 
-// 将版本号字符串转换为可比较的整数切片
-func parseVersion(version string) []int {
-	// 移除可能的'v'前缀
-	if strings.HasPrefix(version, "v") {
-		version = version[1:]
-	}
+// Version 表示一个可比较的版本号
+// 支持各生态的版本格式：
+//   - 语义化版本：18.20.4、v20.11.1、9.0.100-preview.7.24407.12
+//   - Go：1.22.3、1.22rc1、1.21beta2、go1.22.3
+//   - PEP 440：3.13.0a4、3.12.0b1、3.11.0rc2、1.0.dev1、1.0.post1
+//   - Java：17.0.9+9、21+35、1.8.0_392
+type Version struct {
+	original string
+	segments []int    // 数字部分，例如 1.22.3 -> [1 22 3]
+	pre      []string // 预发布（或后发布）标识，例如 1.22rc1 -> [rc 1]
+	build    []string // 构建信息，例如 17.0.9+9 -> [9]
+}
 
-	parts := strings.Split(version, ".")
-	result := make([]int, len(parts))
-	for i, part := range parts {
-		num, _ := strconv.Atoi(part)
-		result[i] = num
-	}
-	return result
+// 预发布标识的先后顺序，正式版本位于 rc 和 post 之间
+const (
+	rankDev = iota
+	rankAlpha
+	rankBeta
+	rankPreview
+	rankRC
+	rankRelease
+	rankPost
+)
+
+// prereleaseRanks 已知的预发布标识，未知的标识按 preview 处理并按字母顺序比较
+var prereleaseRanks = map[string]int{
+	"dev":     rankDev,
+	"a":       rankAlpha,
+	"alpha":   rankAlpha,
+	"b":       rankBeta,
+	"beta":    rankBeta,
+	"pre":     rankPreview,
+	"preview": rankPreview,
+	"c":       rankRC,
+	"rc":      rankRC,
+	"post":    rankPost,
+	"rev":     rankPost,
+	"r":       rankPost,
 }
 
-// 比较两个版本号
-func compareVersions(v1, v2 string) bool {
-	parts1 := parseVersion(v1)
-	parts2 := parseVersion(v2)
+// ParseVersion 解析版本号
+// 允许带有字母前缀（如 v、go、jdk-），+ 之后的内容为构建信息
+func ParseVersion(version string) (Version, error) {
+	v := Version{original: version}
+	s := strings.TrimSpace(version)
+
+	// 去掉前缀
+	start := strings.IndexFunc(s, isDigit)
+	if start < 0 || strings.TrimLeftFunc(s[:start], func(r rune) bool { return isLetter(r) || r == '-' }) != "" {
+		return Version{}, fmt.Errorf("无效的版本号: %q", version)
+	}
+	s = s[start:]
 
-	// 使用最短的长度进行比较
-	minLen := len(parts1)
-	if len(parts2) < minLen {
-		minLen = len(parts2)
+	// 构建信息
+	if idx := strings.Index(s, "+"); idx >= 0 {
+		build, err := splitIdentifiers(s[idx+1:])
+		if err != nil || len(build) == 0 {
+			return Version{}, fmt.Errorf("无效的构建信息: %q", version)
+		}
+		v.build = build
+		s = s[:idx]
 	}
 
-	for i := 0; i < minLen; i++ {
-		if parts1[i] != parts2[i] {
-			return parts1[i] > parts2[i]
+	// 数字部分，Java 8 的 1.8.0_392 中下划线同样视为分隔符
+	for {
+		end := strings.IndexFunc(s, func(r rune) bool { return !isDigit(r) })
+		if end < 0 {
+			end = len(s)
+		}
+		if end == 0 {
+			break
 		}
+
+		v.segments = append(v.segments, atoiSaturated(s[:end]))
+		s = s[end:]
+
+		if len(s) > 1 && (s[0] == '.' || s[0] == '_') && isDigit(rune(s[1])) {
+			s = s[1:]
+			continue
+		}
+		break
+	}
+
+	// 剩余部分为预发布标识
+	pre, err := splitIdentifiers(s)
+	if err != nil {
+		return Version{}, fmt.Errorf("无效的版本号: %q", version)
 	}
+	v.pre = pre
 
-	// 如果前面的部分都相同，较长的版本号较大
-	return len(parts1) > len(parts2)
+	return v, nil
 }
 
-// SortVersionsDesc 按版本号降序排序字符串切片
-func SortVersionsDesc(versions []string) {
-	sort.Slice(versions, func(i, j int) bool {
-		return compareVersions(versions[i], versions[j])
-	})
+// String 返回原始版本号字符串
+func (v Version) String() string {
+	return v.original
+}
+
+// Segments 返回版本号的数字部分
+func (v Version) Segments() []int {
+	return append([]int{}, v.segments...)
+}
+
+// Major 返回主版本号
+func (v Version) Major() int {
+	return v.segment(0)
+}
+
+// Minor 返回次版本号，不存在时为0
+func (v Version) Minor() int {
+	return v.segment(1)
+}
+
+// Patch 返回修订号，不存在时为0
+func (v Version) Patch() int {
+	return v.segment(2)
+}
+
+// Prerelease 返回预发布标识，例如 rc.1
+func (v Version) Prerelease() string {
+	return strings.Join(v.pre, ".")
 }
 
-// ParseVersion 将版本号解析为可比较的格式
-func ParseVersion(version string) ([]int, error) {
-	// 移除可能的'v'前缀
-	if strings.HasPrefix(version, "v") {
-		version = version[1:]
+// Build 返回构建信息，例如 Java 17.0.9+9 中的 9
+func (v Version) Build() string {
+	return strings.Join(v.build, ".")
+}
+
+// IsPrerelease 判断是否为预发布版本（PEP 440 的 post 版本不算预发布）
+func (v Version) IsPrerelease() bool {
+	return len(v.pre) > 0 && identifierRank(v.pre[0]) != rankPost
+}
+
+// Compare 比较两个版本号，返回 -1、0 或 1
+// 预发布版本低于对应的正式版本，构建信息只在其他部分都相同时参与比较
+func (v Version) Compare(other Version) int {
+	if result := v.comparePrecedence(other); result != 0 {
+		return result
 	}
+	return compareIdentifierLists(v.build, other.build)
+}
 
-	parts := strings.Split(version, ".")
-	result := make([]int, len(parts))
-	for i, part := range parts {
-		num, err := strconv.Atoi(part)
-		if err != nil {
-			return nil, err
+// comparePrecedence 比较两个版本号，不考虑构建信息
+func (v Version) comparePrecedence(other Version) int {
+	if result := v.compareSegments(other); result != 0 {
+		return result
+	}
+	return comparePrerelease(v.pre, other.pre)
+}
+
+// compareSegments 比较数字部分，缺少的部分视为0（1.20 与 1.20.0 相等）
+func (v Version) compareSegments(other Version) int {
+	length := max(len(v.segments), len(other.segments))
+	for i := 0; i < length; i++ {
+		n1, n2 := v.segment(i), other.segment(i)
+		if n1 != n2 {
+			if n1 < n2 {
+				return -1
+			}
+			return 1
 		}
-		result[i] = num
 	}
-	return result, nil
+	return 0
 }
 
-// CompareVersions 比较两个版本号，返回:
-// -1 如果 v1 < v2
-//
-//	0 如果 v1 == v2
-//	1 如果 v1 > v2
-func CompareVersions(v1 []int, v2 []int) int {
-	// 使用最短的长度进行比较
-	minLen := len(v1)
-	if len(v2) < minLen {
-		minLen = len(v2)
+func (v Version) segment(index int) int {
+	if index < len(v.segments) {
+		return v.segments[index]
 	}
+	return 0
+}
 
-	for i := 0; i < minLen; i++ {
-		if v1[i] < v2[i] {
-			return -1
+// comparePrerelease 比较预发布标识，空标识表示正式版本
+func comparePrerelease(pre1, pre2 []string) int {
+	for i := 0; ; i++ {
+		switch {
+		case i >= len(pre1) && i >= len(pre2):
+			return 0
+		case i >= len(pre1):
+			return -compareWithEnd(pre2[i:], i == 0)
+		case i >= len(pre2):
+			return compareWithEnd(pre1[i:], i == 0)
 		}
-		if v1[i] > v2[i] {
-			return 1
+
+		if result := compareIdentifier(pre1[i], pre2[i]); result != 0 {
+			return result
 		}
 	}
+}
 
-	// 如果前面的部分都相同，较长的版本号较大
-	if len(v1) < len(v2) {
+// compareWithEnd 比较带有额外标识的版本与没有这些标识的版本
+// 开头的预发布标识低于正式版本；PEP 440 的 dev 更低、post 更高；其他情况按语义化版本，标识更多的较高
+func compareWithEnd(rest []string, atStart bool) int {
+	switch identifierRank(rest[0]) {
+	case rankPost:
+		return 1
+	case rankDev:
+		return -1
+	}
+	if atStart {
 		return -1
 	}
-	if len(v1) > len(v2) {
+	return 1
+}
+
+// compareIdentifierLists 逐个比较标识，标识较少的版本较低
+func compareIdentifierLists(ids1, ids2 []string) int {
+	for i := 0; i < len(ids1) && i < len(ids2); i++ {
+		if result := compareIdentifier(ids1[i], ids2[i]); result != 0 {
+			return result
+		}
+	}
+	switch {
+	case len(ids1) < len(ids2):
+		return -1
+	case len(ids1) > len(ids2):
 		return 1
 	}
 	return 0
 }
 
+// compareIdentifier 比较单个标识：数字按数值比较且低于字母标识，字母标识先按预发布阶段再按字母顺序比较
+func compareIdentifier(id1, id2 string) int {
+	num1, num2 := isNumeric(id1), isNumeric(id2)
+	switch {
+	case num1 && num2:
+		id1, id2 = strings.TrimLeft(id1, "0"), strings.TrimLeft(id2, "0")
+		if len(id1) != len(id2) {
+			if len(id1) < len(id2) {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(id1, id2)
+	case num1:
+		return -1
+	case num2:
+		return 1
+	}
+
+	rank1, rank2 := identifierRank(id1), identifierRank(id2)
+	if rank1 != rank2 {
+		if rank1 < rank2 {
+			return -1
+		}
+		return 1
+	}
+	return strings.Compare(strings.ToLower(id1), strings.ToLower(id2))
+}
+
+func identifierRank(id string) int {
+	if rank, ok := prereleaseRanks[strings.ToLower(id)]; ok {
+		return rank
+	}
+	return rankPreview
+}
+
+// splitIdentifiers 按 . - _ 以及字母与数字的边界拆分标识，例如 rc1 -> [rc 1]，preview.7 -> [preview 7]
+func splitIdentifiers(s string) ([]string, error) {
+	var ids []string
+	current := ""
+	for _, r := range s {
+		switch {
+		case r == '.' || r == '-' || r == '_':
+			if current != "" {
+				ids = append(ids, current)
+			}
+			current = ""
+		case isDigit(r) || isLetter(r):
+			if current != "" && isDigit(r) != isDigit(rune(current[len(current)-1])) {
+				ids = append(ids, current)
+				current = ""
+			}
+			current += string(r)
+		default:
+			return nil, fmt.Errorf("无效的字符: %q", r)
+		}
+	}
+	if current != "" {
+		ids = append(ids, current)
+	}
+	return ids, nil
+}
+
+// atoiSaturated 将数字字符串转换为整数，超出范围时取最大值
+func atoiSaturated(s string) int {
+	const maxInt = int(^uint(0) >> 1)
+	n := 0
+	for _, r := range s {
+		if n > (maxInt-int(r-'0'))/10 {
+			return maxInt
+		}
+		n = n*10 + int(r-'0')
+	}
+	return n
+}
+
+func isDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
+
+func isLetter(r rune) bool {
+	return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+}
+
+func isNumeric(s string) bool {
+	return s != "" && strings.IndexFunc(s, func(r rune) bool { return !isDigit(r) }) < 0
+}
+
+// SortVersionsDesc 按版本号降序排序字符串切片
+// 无法解析的版本排在最后，并按字符串降序排列
+func SortVersionsDesc(versions []string) {
+	type parsedVersion struct {
+		raw     string
+		version Version
+		valid   bool
+	}
+
+	parsed := make([]parsedVersion, len(versions))
+	for i, raw := range versions {
+		version, err := ParseVersion(raw)
+		parsed[i] = parsedVersion{raw: raw, version: version, valid: err == nil}
+	}
+
+	sort.SliceStable(parsed, func(i, j int) bool {
+		switch {
+		case parsed[i].valid && parsed[j].valid:
+			return parsed[i].version.Compare(parsed[j].version) > 0
+		case parsed[i].valid != parsed[j].valid:
+			return parsed[i].valid
+		default:
+			return parsed[i].raw > parsed[j].raw
+		}
+	})
+
+	for i := range parsed {
+		versions[i] = parsed[i].raw
+	}
+}
+
 // CompareVersionsStr 比较两个版本号字符串，返回:
 // -1 如果 v1 < v2
 //
 //	0 如果 v1 == v2
 //	1 如果 v1 > v2
+//
+// 无法解析的版本低于有效版本，两者都无法解析时按字符串比较
 func CompareVersionsStr(v1, v2 string) int {
-	parts1, err1 := ParseVersion(v1)
-	parts2, err2 := ParseVersion(v2)
+	version1, err1 := ParseVersion(v1)
+	version2, err2 := ParseVersion(v2)
 
-	// 如果解析出错，回退到字符串比较
-	if err1 != nil || err2 != nil {
-		if v1 == v2 {
-			return 0
-		}
-		if v1 > v2 {
-			return 1
-		}
+	switch {
+	case err1 == nil && err2 == nil:
+		return version1.Compare(version2)
+	case err1 == nil:
+		return 1
+	case err2 == nil:
 		return -1
 	}
-
-	return CompareVersions(parts1, parts2)
+	return strings.Compare(v1, v2)
 }
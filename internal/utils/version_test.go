@@ -0,0 +1,108 @@
+package utils
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		version  string
+		segments []int
+		pre      string
+		build    string
+		wantErr  bool
+	}{
+		{version: "18.20.4", segments: []int{18, 20, 4}},
+		{version: "v20.11.1", segments: []int{20, 11, 1}},
+		{version: "go1.22.3", segments: []int{1, 22, 3}},
+		{version: "1.22rc1", segments: []int{1, 22}, pre: "rc.1"},
+		{version: "1.21beta2", segments: []int{1, 21}, pre: "beta.2"},
+		{version: "3.13.0a4", segments: []int{3, 13, 0}, pre: "a.4"},
+		{version: "1.0.post1", segments: []int{1, 0}, pre: "post.1"},
+		{version: "9.0.100-preview.7.24407.12", segments: []int{9, 0, 100}, pre: "preview.7.24407.12"},
+		{version: "17.0.9+9", segments: []int{17, 0, 9}, build: "9"},
+		{version: "21+35", segments: []int{21}, build: "35"},
+		{version: "1.8.0_392", segments: []int{1, 8, 0, 392}},
+		{version: "jdk-17.0.9+9", segments: []int{17, 0, 9}, build: "9"},
+		{version: "", wantErr: true},
+		{version: "latest", wantErr: true},
+		{version: "^1.2", wantErr: true},
+		{version: "17+", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			v, err := ParseVersion(tt.version)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ParseVersion(%q) 期望返回错误", tt.version)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseVersion(%q) 失败: %v", tt.version, err)
+			}
+			if !slices.Equal(v.Segments(), tt.segments) || v.Prerelease() != tt.pre || v.Build() != tt.build {
+				t.Errorf("ParseVersion(%q) = %v %q %q，期望 %v %q %q",
+					tt.version, v.Segments(), v.Prerelease(), v.Build(), tt.segments, tt.pre, tt.build)
+			}
+		})
+	}
+}
+
+func TestVersionCompare(t *testing.T) {
+	tests := []struct {
+		v1, v2 string
+		want   int
+	}{
+		// 语义化版本
+		{"18.20.4", "18.20.4", 0},
+		{"18.9.0", "18.10.0", -1},
+		{"v20.11.1", "20.11.0", 1},
+		{"1.20", "1.20.0", 0},
+		{"9.0.100-preview.7", "9.0.100", -1},
+		{"9.0.100-preview.7", "9.0.100-rc.1", -1},
+		{"1.0.0-alpha.1", "1.0.0-alpha.beta", -1},
+
+		// Go
+		{"1.22rc1", "1.22", -1},
+		{"1.22rc1", "1.22rc2", -1},
+		{"1.21beta2", "1.21rc1", -1},
+		{"1.22", "1.22.1", -1},
+		{"go1.22.3", "1.22.3", 0},
+
+		// PEP 440
+		{"3.13.0a4", "3.13.0b1", -1},
+		{"3.13.0b1", "3.13.0rc2", -1},
+		{"3.13.0rc2", "3.13.0", -1},
+		{"3.13.0", "3.13.0.post1", -1},
+		{"1.0.dev1", "1.0a1", -1},
+		{"1.0.dev1", "1.0", -1},
+
+		// 构建信息只在其他部分相同时参与比较
+		{"17.0.9+9", "17.0.9+10", -1},
+		{"17.0.9", "17.0.9+9", -1},
+		{"17.0.10+1", "17.0.9+9", 1},
+		{"1.8.0_392", "1.8.0_41", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.v1+" vs "+tt.v2, func(t *testing.T) {
+			v1, err := ParseVersion(tt.v1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			v2, err := ParseVersion(tt.v2)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := v1.Compare(v2); got != tt.want {
+				t.Errorf("Compare(%q, %q) = %d，期望 %d", tt.v1, tt.v2, got, tt.want)
+			}
+			if got := v2.Compare(v1); got != -tt.want {
+				t.Errorf("Compare(%q, %q) = %d，期望 %d", tt.v2, tt.v1, got, -tt.want)
+			}
+		})
+	}
+}